@@ -7,6 +7,7 @@
 package semver
 
 import (
+	"fmt"
 	"testing"
 )
 
@@ -117,3 +118,36 @@ func BenchmarkVersionComparator(b *testing.B) {
 	// Results for v0.12.0:  :-D
 	// BenchmarkVersionComparator-12    	  101772	     11720 ns/op	       0 B/op	       0 allocs/op
 }
+
+func BenchmarkResolver(b *testing.B) {
+	// 16 packages with 20 releases each, every release depends on the next
+	// package with a constraint that forces some backtracking
+	names := "ABCDEFGHIJKLMNOP"
+	arch := NewResolver[*customRel]()
+	for i := range len(names) {
+		for k := range 20 {
+			var dependencies []*customDep
+			if i+1 < len(names) {
+				dependencies = deps(fmt.Sprintf("%c>=1.%d.0", names[i+1], k/4))
+			}
+			arch.AddRelease(rel(names[i:i+1], fmt.Sprintf("1.%d.0", k), dependencies))
+		}
+	}
+	root := rel("R", "1.0.0", deps("A^1.0.0", "P<1.2.0"))
+	arch.AddRelease(root)
+
+	// Disable the debug output of the tests
+	testDebug := debug
+	debug = noopDebug
+	defer func() { debug = testDebug }()
+	for name, algorithm := range map[string]Algorithm{"Backtracking": Backtracking, "PubGrub": PubGrub} {
+		arch.SetAlgorithm(algorithm)
+		b.Run(name, func(b *testing.B) {
+			for range b.N {
+				if arch.Resolve(root) == nil {
+					b.Fatal("no solution found")
+				}
+			}
+		})
+	}
+}
@@ -35,6 +35,10 @@ func (v *Version) MarshalBinary() ([]byte, error) {
 	_, _ = res.Write(intBuff[:])
 	binary.BigEndian.PutUint32(intBuff[:], uint32(v.build))
 	_, _ = res.Write(intBuff[:])
+	if v.revision != "" {
+		// Optional fourth number of the versions parsed with ParseWithOptions
+		_, _ = res.Write(marshalByteArray([]byte(v.revision)))
+	}
 	return res.Bytes(), nil
 }
 
@@ -58,7 +62,12 @@ func (v *Version) UnmarshalBinary(data []byte) error {
 	v.minor, data = decodeInt(data)
 	v.patch, data = decodeInt(data)
 	v.prerelease, data = decodeInt(data)
-	v.build, _ = decodeInt(data)
+	v.build, data = decodeInt(data)
+	v.spelling, v.revision = "", ""
+	if len(data) > 0 {
+		buff, _ = decodeArray(data)
+		v.revision = string(buff)
+	}
 	return nil
 }
 
@@ -66,7 +75,17 @@ func (v *Version) UnmarshalBinary(data []byte) error {
 func (v *RelaxedVersion) MarshalBinary() ([]byte, error) {
 	res := new(bytes.Buffer)
 	if len(v.customversion) > 0 {
-		_, _ = res.Write([]byte{0})
+		if v.coerced != nil || v.ordering.resolve() != OrderingAlphabetical {
+			// custom version with options: ordering and coerce flag, the
+			// alphabetical versions keep the original format
+			coerce := byte(0)
+			if v.coerced != nil {
+				coerce = 1
+			}
+			_, _ = res.Write([]byte{2, byte(v.ordering), coerce})
+		} else {
+			_, _ = res.Write([]byte{0})
+		}
 		_, _ = res.Write(marshalByteArray(v.customversion))
 		return res.Bytes(), nil
 	}
@@ -78,13 +97,25 @@ func (v *RelaxedVersion) MarshalBinary() ([]byte, error) {
 
 // UnmarshalBinary implements encoding.BinaryUnmarshaler
 func (v *RelaxedVersion) UnmarshalBinary(data []byte) error {
-	if data[0] == 0 {
-		v.customversion, _ = decodeArray(data[1:])
+	if data[0] == 0 || data[0] == 2 {
 		v.version = nil
+		v.coerced = nil
+		v.ordering = DefaultCustomOrdering
+		if data[0] == 0 {
+			v.customversion, _ = decodeArray(data[1:])
+			return nil
+		}
+		v.ordering = CustomOrdering(data[1])
+		v.customversion, _ = decodeArray(data[3:])
+		if data[2] == 1 {
+			v.coerced, _ = Coerce(string(v.customversion))
+		}
 		return nil
 	}
 
 	v.customversion = nil
+	v.coerced = nil
+	v.ordering = OrderingDefault
 	v.version = &Version{}
 	return v.version.UnmarshalBinary(data[1:])
 }
@@ -10,6 +10,7 @@ import (
 	"bytes"
 	"encoding/gob"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -49,6 +50,32 @@ func TestGOBEncoderVersion(t *testing.T) {
 	}
 }
 
+func TestGOBEncoderVersionWithOptions(t *testing.T) {
+	v, err := ParseWithOptions("v1.02.3.4-rc", ParseOptions{AllowPrefix: true, AllowLeadingZeros: true, AllowFourComponents: true})
+	require.NoError(t, err)
+
+	// The fourth number is encoded, the spelling is not
+	data := new(bytes.Buffer)
+	require.NoError(t, gob.NewEncoder(data).Encode(v))
+	var u *Version
+	require.NoError(t, gob.NewDecoder(data).Decode(&u))
+	require.Equal(t, "1.2.3.4-rc", u.String())
+	require.Zero(t, v.CompareTo(u))
+
+	for _, in := range []string{"v1.2.3", "1.2.3.4"} {
+		v, err := ParseWithOptions(in, ParseOptions{AllowPrefix: true, AllowLeadingZeros: true, AllowFourComponents: true})
+		require.NoError(t, err)
+		data, err := v.MarshalBinary()
+		require.NoError(t, err)
+		require.NoError(t, u.UnmarshalBinary(data))
+		require.Zero(t, v.CompareTo(u))
+		require.Equal(t, strings.TrimPrefix(in, "v"), u.String())
+	}
+	data2, _ := MustParse("2.0.0").MarshalBinary()
+	require.NoError(t, u.UnmarshalBinary(data2))
+	require.Equal(t, MustParse("2.0.0"), u)
+}
+
 func TestGOBEncoderRelaxedVersion(t *testing.T) {
 	check := func(testVersion string) {
 		v := ParseRelaxed(testVersion)
@@ -65,6 +92,42 @@ func TestGOBEncoderRelaxedVersion(t *testing.T) {
 	}
 	check("1.2.3-aaa.4.5.6+bbb.7.8.9")
 	check("asdasdasd-1.2.3-aaa.4.5.6+bbb.7.8.9")
+
+	// The alphabetical versions are encoded in the original format
+	data, err := ParseRelaxed("build-10").MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, []byte{0, 0, 0, 0, 8, 'b', 'u', 'i', 'l', 'd', '-', '1', '0'}, data)
+	data, err = ParseRelaxedWithOptions("build-10", RelaxedOptions{Ordering: OrderingAlphabetical}).MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, []byte{0, 0, 0, 0, 8, 'b', 'u', 'i', 'l', 'd', '-', '1', '0'}, data)
+
+	v := ParseRelaxedWithOptions("release-1.4_final", RelaxedOptions{Coerce: true})
+	data, err = v.MarshalBinary()
+	require.NoError(t, err)
+	var u RelaxedVersion
+	require.NoError(t, u.UnmarshalBinary(data))
+	require.Equal(t, v, &u)
+	require.True(t, u.GreaterThan(ParseRelaxed("1.4.0")))
+
+	v = ParseRelaxedWithOptions("build-10", RelaxedOptions{Ordering: OrderingNatural})
+	data, err = v.MarshalBinary()
+	require.NoError(t, err)
+	require.NoError(t, u.UnmarshalBinary(data))
+	require.Equal(t, v, &u)
+	require.True(t, u.GreaterThan(ParseRelaxedWithOptions("build-9", RelaxedOptions{Ordering: OrderingNatural})))
+
+	// The data without ordering follows the DefaultCustomOrdering
+	DefaultCustomOrdering = OrderingNatural
+	defer func() { DefaultCustomOrdering = OrderingAlphabetical }()
+	oldFormat := append([]byte{0}, marshalByteArray([]byte("build-10"))...)
+	require.NoError(t, u.UnmarshalBinary(oldFormat))
+	require.Equal(t, "build-10", u.String())
+	require.True(t, u.GreaterThan(ParseRelaxed("build-9")))
+	data, err = u.MarshalBinary()
+	require.NoError(t, err)
+	DefaultCustomOrdering = OrderingAlphabetical
+	require.NoError(t, u.UnmarshalBinary(data))
+	require.True(t, u.GreaterThan(ParseRelaxedWithOptions("build-9", RelaxedOptions{Ordering: OrderingNatural})))
 }
 
 func BenchmarkBinaryDecoding(b *testing.B) {
@@ -0,0 +1,116 @@
+//
+// Copyright 2018-2025 Cristian Maglie. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+
+package semver
+
+import (
+	"strconv"
+	"strings"
+)
+
+// NewVersion builds a Version from its components. The prerelease and build
+// identifiers are validated with the same rules of Parse: they must not be
+// empty, must contain only ASCII alphanumerics and hyphens, and numeric
+// pre-release identifiers must not be prefixed with zero. If an identifier
+// is not valid a *ParseError with the identifier as Input is returned.
+func NewVersion(major, minor, patch uint64, prerelease []string, build []string) (*Version, error) {
+	for _, id := range prerelease {
+		if err := validateIdentifier(id, ComponentPrerelease); err != nil {
+			return nil, err
+		}
+	}
+	for _, id := range build {
+		if err := validateIdentifier(id, ComponentBuild); err != nil {
+			return nil, err
+		}
+	}
+
+	raw := strconv.FormatUint(major, 10)
+	res := &Version{major: len(raw)}
+	raw += "." + strconv.FormatUint(minor, 10)
+	res.minor = len(raw)
+	raw += "." + strconv.FormatUint(patch, 10)
+	res.patch = len(raw)
+	if len(prerelease) > 0 {
+		raw += "-" + strings.Join(prerelease, ".")
+	}
+	res.prerelease = len(raw)
+	if len(build) > 0 {
+		raw += "+" + strings.Join(build, ".")
+	}
+	res.build = len(raw)
+	res.raw = raw
+	res.bytes = []byte(raw)
+	return res, nil
+}
+
+// validateIdentifier checks a pre-release or build identifier
+func validateIdentifier(id string, component VersionComponent) error {
+	fail := func(kind error, offset int) error {
+		return &ParseError{Input: id, Offset: offset, Component: component, Kind: kind}
+	}
+	if id == "" {
+		return fail(ErrEmptyIdentifier, 0)
+	}
+	for i := range len(id) {
+		if !identifier[id[i]] {
+			return fail(ErrInvalidCharacter, i)
+		}
+	}
+	if component == ComponentPrerelease && len(id) > 1 && id[0] == '0' && isNumericIdentifier(id) {
+		return fail(ErrLeadingZero, 0)
+	}
+	return nil
+}
+
+// VersionBuilder builds a Version one component at a time, for example:
+//
+//	v, err := NewVersionBuilder().Major(1).Minor(2).Prerelease("rc", "1").Version()
+type VersionBuilder struct {
+	major, minor, patch uint64
+	prerelease, build   []string
+}
+
+// NewVersionBuilder returns a VersionBuilder for the version 0.0.0
+func NewVersionBuilder() *VersionBuilder {
+	return &VersionBuilder{}
+}
+
+// Major sets the major number
+func (b *VersionBuilder) Major(n uint64) *VersionBuilder {
+	b.major = n
+	return b
+}
+
+// Minor sets the minor number
+func (b *VersionBuilder) Minor(n uint64) *VersionBuilder {
+	b.minor = n
+	return b
+}
+
+// Patch sets the patch number
+func (b *VersionBuilder) Patch(n uint64) *VersionBuilder {
+	b.patch = n
+	return b
+}
+
+// Prerelease sets the pre-release identifiers
+func (b *VersionBuilder) Prerelease(identifiers ...string) *VersionBuilder {
+	b.prerelease = identifiers
+	return b
+}
+
+// Build sets the build metadata identifiers
+func (b *VersionBuilder) Build(identifiers ...string) *VersionBuilder {
+	b.build = identifiers
+	return b
+}
+
+// Version returns the built Version, or an error if an identifier is not
+// valid (see NewVersion).
+func (b *VersionBuilder) Version() (*Version, error) {
+	return NewVersion(b.major, b.minor, b.patch, b.prerelease, b.build)
+}
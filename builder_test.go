@@ -0,0 +1,73 @@
+//
+// Copyright 2018-2025 Cristian Maglie. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+
+package semver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewVersion(t *testing.T) {
+	valid := func(expected string, major, minor, patch uint64, prerelease, build []string) {
+		v, err := NewVersion(major, minor, patch, prerelease, build)
+		require.NoError(t, err)
+		require.Equal(t, expected, v.String())
+
+		// The internal layout is the same produced by Parse
+		parsed := MustParse(expected)
+		require.Equal(t, parsed, v)
+		require.Equal(t, parsed.SortableString(), v.SortableString())
+		require.Zero(t, parsed.CompareTo(v))
+	}
+	valid("0.0.0", 0, 0, 0, nil, nil)
+	valid("1.2.3", 1, 2, 3, nil, nil)
+	valid("1.2.3", 1, 2, 3, []string{}, []string{})
+	valid("10.20.30-rc.1", 10, 20, 30, []string{"rc", "1"}, nil)
+	valid("1.0.0+build.001", 1, 0, 0, nil, []string{"build", "001"})
+	valid("1.0.0-0.x-y.-z+-", 1, 0, 0, []string{"0", "x-y", "-z"}, []string{"-"})
+	valid("18446744073709551615.0.0", 18446744073709551615, 0, 0, nil, nil)
+
+	invalid := func(prerelease, build []string, kind error, component VersionComponent, offset int) {
+		v, err := NewVersion(1, 2, 3, prerelease, build)
+		require.Nil(t, v)
+		require.ErrorIs(t, err, kind)
+		var parseErr *ParseError
+		require.ErrorAs(t, err, &parseErr)
+		require.Equal(t, component, parseErr.Component)
+		require.Equal(t, offset, parseErr.Offset)
+	}
+	invalid([]string{"rc", ""}, nil, ErrEmptyIdentifier, ComponentPrerelease, 0)
+	invalid([]string{"01"}, nil, ErrLeadingZero, ComponentPrerelease, 0)
+	invalid([]string{"rc.1"}, nil, ErrInvalidCharacter, ComponentPrerelease, 2)
+	invalid(nil, []string{""}, ErrEmptyIdentifier, ComponentBuild, 0)
+	invalid(nil, []string{"a_b"}, ErrInvalidCharacter, ComponentBuild, 1)
+
+	// Leading zeros are allowed in build identifiers
+	_, err := NewVersion(1, 2, 3, []string{"0"}, []string{"01"})
+	require.NoError(t, err)
+}
+
+func TestVersionBuilder(t *testing.T) {
+	v, err := NewVersionBuilder().Version()
+	require.NoError(t, err)
+	require.Equal(t, "0.0.0", v.String())
+
+	b := NewVersionBuilder().Major(1).Minor(2).Patch(3).Prerelease("rc", "1").Build("sha", "abc")
+	v, err = b.Version()
+	require.NoError(t, err)
+	require.Equal(t, "1.2.3-rc.1+sha.abc", v.String())
+	require.Equal(t, MustParse("1.2.3-rc.1+sha.abc"), v)
+
+	v, err = b.Prerelease().Build().Patch(4).Version()
+	require.NoError(t, err)
+	require.Equal(t, "1.2.4", v.String())
+	require.True(t, v.GreaterThan(MustParse("1.2.3")))
+
+	_, err = b.Prerelease("00").Version()
+	require.ErrorIs(t, err, ErrLeadingZero)
+}
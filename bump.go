@@ -0,0 +1,161 @@
+//
+// Copyright 2018-2025 Cristian Maglie. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+
+package semver
+
+import (
+	"strings"
+)
+
+// IncMajor returns the next major version: "1.2.3" becomes "2.0.0". The
+// pre-release of a major version is promoted to the release itself: "2.0.0-rc"
+// becomes "2.0.0". The build metadata is dropped and the resulting version
+// has the same number of components of v: "1.2" becomes "2.0".
+//
+// The bumped versions keep the "v" prefix of the versions parsed with
+// ParseWithOptions, while the leading zeros are removed. The fourth number is
+// kept by the methods that do not increment a number and it's reset
+// otherwise: "1.2.3.4-rc" becomes "1.2.3.4" with IncPatch and "1.2.4" with
+// IncMinor.
+func (v *Version) IncMajor() *Version {
+	major, minor, patch, _ := versionParts(v)
+	if !v.IsPrerelease() || minor != "0" || patch != "0" || v.revision != "" {
+		major, minor, patch = incNumber(major), "0", "0"
+		return v.mustDerive(major, minor, patch, "", versionComponents(v), "", "")
+	}
+	return v.mustDerive(major, minor, patch, v.revision, versionComponents(v), "", "")
+}
+
+// IncMinor returns the next minor version: "1.2.3" becomes "1.3.0". The
+// pre-release of a minor version is promoted to the release itself:
+// "1.3.0-rc" becomes "1.3.0". The build metadata is dropped.
+func (v *Version) IncMinor() *Version {
+	major, minor, patch, _ := versionParts(v)
+	if !v.IsPrerelease() || patch != "0" || v.revision != "" {
+		minor, patch = incNumber(minor), "0"
+		return v.mustDerive(major, minor, patch, "", max(versionComponents(v), 2), "", "")
+	}
+	return v.mustDerive(major, minor, patch, v.revision, max(versionComponents(v), 2), "", "")
+}
+
+// IncPatch returns the next patch version: "1.2.3" becomes "1.2.4". A
+// pre-release is promoted to the release itself: "1.2.4-rc" becomes "1.2.4".
+// The build metadata is dropped.
+func (v *Version) IncPatch() *Version {
+	major, minor, patch, _ := versionParts(v)
+	if !v.IsPrerelease() {
+		return v.mustDerive(major, minor, incNumber(patch), "", 3, "", "")
+	}
+	return v.mustDerive(major, minor, patch, v.revision, versionComponents(v), "", "")
+}
+
+// IncPrerelease returns the next pre-release version. If v is a pre-release
+// with the given identifier its last numeric part is incremented, otherwise
+// the counter of the identifier starts from 0:
+//
+//	"1.2.3-rc.1" with "rc" becomes "1.2.3-rc.2"
+//	"1.2.3-alpha.1" with "beta" becomes "1.2.3-beta.0"
+//	"1.2.3" with "rc" becomes "1.2.4-rc.0"
+//
+// If identifier is empty the last numeric part of the pre-release is
+// incremented, or ".0" is appended if there is none. The build metadata is
+// dropped. An error is returned if identifier is not valid.
+func (v *Version) IncPrerelease(identifier string) (*Version, error) {
+	major, minor, patch, prerelease := versionParts(v)
+	components := versionComponents(v)
+	if !v.IsPrerelease() {
+		patch = incNumber(patch)
+		components = 3
+		if identifier == "" {
+			return v.derive(major, minor, patch, "", components, "0", "")
+		}
+		return v.derive(major, minor, patch, "", components, identifier+".0", "")
+	}
+
+	if identifier == "" {
+		ids := strings.Split(prerelease, ".")
+		for i := len(ids) - 1; i >= 0; i-- {
+			if isNumericIdentifier(ids[i]) {
+				ids[i] = incNumber(ids[i])
+				return v.derive(major, minor, patch, v.revision, components, strings.Join(ids, "."), "")
+			}
+		}
+		return v.derive(major, minor, patch, v.revision, components, prerelease+".0", "")
+	}
+	if n, found := strings.CutPrefix(prerelease, identifier+"."); found && isNumericIdentifier(n) {
+		return v.derive(major, minor, patch, v.revision, components, identifier+"."+incNumber(n), "")
+	}
+	return v.derive(major, minor, patch, v.revision, components, identifier+".0", "")
+}
+
+// WithPrerelease returns a copy of v with the given pre-release, the build
+// metadata is kept. An empty prerelease removes the pre-release part. An
+// error is returned if prerelease is not valid.
+func (v *Version) WithPrerelease(prerelease string) (*Version, error) {
+	major, minor, patch, _ := versionParts(v)
+	return v.derive(major, minor, patch, v.revision, versionComponents(v), prerelease, v.BuildMetadata())
+}
+
+// WithBuild returns a copy of v with the given build metadata. An empty build
+// removes the build metadata. An error is returned if build is not valid.
+func (v *Version) WithBuild(build string) (*Version, error) {
+	major, minor, patch, prerelease := versionParts(v)
+	return v.derive(major, minor, patch, v.revision, versionComponents(v), prerelease, build)
+}
+
+// derive parses a version built from the given parts, keeping the "v"
+// prefix of v
+func (v *Version) derive(major, minor, patch, revision string, components int, prerelease, build string) (*Version, error) {
+	res := formatVersion(major, minor, patch, components, prerelease, build)
+	if revision != "" {
+		res = formatVersion(major, minor, patch+"."+revision, components, prerelease, build)
+	}
+	if v.spelling != "" && (v.spelling[0] == 'v' || v.spelling[0] == 'V') {
+		res = v.spelling[:1] + res
+	}
+	return ParseWithOptions(res, ParseOptions{AllowPrefix: true, AllowFourComponents: revision != ""})
+}
+
+// mustDerive is like derive but panics if the version is not valid
+func (v *Version) mustDerive(major, minor, patch, revision string, components int, prerelease, build string) *Version {
+	res, err := v.derive(major, minor, patch, revision, components, prerelease, build)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+// formatVersion returns the version string with the given number of numeric
+// components
+func formatVersion(major, minor, patch string, components int, prerelease, build string) string {
+	res := major
+	if components > 1 {
+		res += "." + minor
+	}
+	if components > 2 {
+		res += "." + patch
+	}
+	if prerelease != "" {
+		res += "-" + prerelease
+	}
+	if build != "" {
+		res += "+" + build
+	}
+	return res
+}
+
+// isNumericIdentifier returns true if the pre-release identifier is a number
+func isNumericIdentifier(id string) bool {
+	if id == "" {
+		return false
+	}
+	for i := range len(id) {
+		if !isNumeric(id[i]) {
+			return false
+		}
+	}
+	return true
+}
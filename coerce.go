@@ -0,0 +1,116 @@
+//
+// Copyright 2018-2025 Cristian Maglie. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+
+package semver
+
+import (
+	"strings"
+)
+
+// CoerceReport describes the changes made by Coerce to the input string
+type CoerceReport struct {
+	// Exact is true if the input is a valid version and it has not been changed
+	Exact bool
+	// Prefix is the text discarded before the version
+	Prefix string
+	// Suffix is the text discarded after the version
+	Suffix string
+	// Padded is true if the missing minor or patch numbers have been added
+	Padded bool
+	// LeadingZeros is true if the leading zeros of some numbers have been removed
+	LeadingZeros bool
+	// ConvertedPrerelease is true if the text following the numbers, separated
+	// by a dot, has been converted into a pre-release (like "beta3" in "2.0.0.beta3")
+	ConvertedPrerelease bool
+}
+
+// Coerce extracts the most plausible version from a string that is not a
+// valid version. The first group of up to three numbers separated by dots
+// found in the input is used as major, minor and patch, for example
+// "release-1.4_final" is coerced to "1.4.0". An identifier following the
+// numbers, separated by a hyphen or by a dot, is used as pre-release, like in
+// "2.0.0.beta3" that becomes "2.0.0-beta3", and the same applies to the build
+// metadata separated by a plus.
+//
+// The returned CoerceReport describes the changes made to the input. If the
+// input does not contain any number a nil Version is returned.
+func Coerce(in string) (*Version, CoerceReport) {
+	if v, err := Parse(in); err == nil {
+		return v, CoerceReport{Exact: true}
+	}
+
+	report := CoerceReport{}
+	start := strings.IndexFunc(in, func(c rune) bool { return c >= '0' && c <= '9' })
+	if start == -1 {
+		report.Prefix = in
+		return nil, report
+	}
+	report.Prefix = in[:start]
+
+	curr := start
+	number := func() string {
+		begin := curr
+		for curr < len(in) && isNumeric(in[curr]) {
+			curr++
+		}
+		n := in[begin:curr]
+		if len(n) > 1 && n[0] == '0' {
+			report.LeadingZeros = true
+			if n = strings.TrimLeft(n, "0"); n == "" {
+				n = "0"
+			}
+		}
+		return n
+	}
+	// identifiers reads a dot separated list of identifiers
+	identifiers := func(prerelease bool) string {
+		var res []string
+		for {
+			begin := curr
+			for curr < len(in) && isIdentifier(in[curr]) {
+				curr++
+			}
+			id := in[begin:curr]
+			if prerelease && len(id) > 1 && id[0] == '0' && isNumericIdentifier(id) {
+				report.LeadingZeros = true
+				if id = strings.TrimLeft(id, "0"); id == "" {
+					id = "0"
+				}
+			}
+			res = append(res, id)
+			if curr+1 >= len(in) || in[curr] != '.' || !isIdentifier(in[curr+1]) {
+				return strings.Join(res, ".")
+			}
+			curr++
+		}
+	}
+
+	numbers := []string{number()}
+	for len(numbers) < 3 && curr+1 < len(in) && in[curr] == '.' && isNumeric(in[curr+1]) {
+		curr++
+		numbers = append(numbers, number())
+	}
+	if len(numbers) < 3 {
+		report.Padded = true
+		numbers = append(numbers, "0", "0")[:3]
+	}
+	res := strings.Join(numbers, ".")
+
+	if curr+1 < len(in) {
+		sep, next := in[curr], in[curr+1]
+		if sep == '-' && isIdentifier(next) || sep == '.' && !isNumeric(next) && isIdentifier(next) {
+			curr++
+			res += "-" + identifiers(true)
+			report.ConvertedPrerelease = sep == '.'
+		}
+	}
+	if curr+1 < len(in) && in[curr] == '+' && isIdentifier(in[curr+1]) {
+		curr++
+		res += "+" + identifiers(false)
+	}
+	report.Suffix = in[curr:]
+	return MustParse(res), report
+}
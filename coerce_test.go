@@ -0,0 +1,43 @@
+//
+// Copyright 2018-2025 Cristian Maglie. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+
+package semver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoerce(t *testing.T) {
+	check := func(in, expected string, report CoerceReport) {
+		v, r := Coerce(in)
+		if expected == "" {
+			require.Nil(t, v, in)
+		} else {
+			require.NotNil(t, v, in)
+			require.Equal(t, expected, v.String(), in)
+		}
+		require.Equal(t, report, r, in)
+	}
+	check("1.2.3-rc.1+build", "1.2.3-rc.1+build", CoerceReport{Exact: true})
+	check("1.2", "1.2", CoerceReport{Exact: true})
+	check("release-1.4_final", "1.4.0", CoerceReport{Prefix: "release-", Suffix: "_final", Padded: true})
+	check("2.0.0.beta3", "2.0.0-beta3", CoerceReport{ConvertedPrerelease: true})
+	check("v1.2.3", "1.2.3", CoerceReport{Prefix: "v"})
+	check("v01.002.0", "1.2.0", CoerceReport{Prefix: "v", LeadingZeros: true})
+	check("1.2.3.4", "1.2.3", CoerceReport{Suffix: ".4"})
+	check("1.2.3.4.beta", "1.2.3", CoerceReport{Suffix: ".4.beta"})
+	check("foo 3 bar", "3.0.0", CoerceReport{Prefix: "foo ", Suffix: " bar", Padded: true})
+	check("1.2.3-rc.01_x", "1.2.3-rc.1", CoerceReport{LeadingZeros: true, Suffix: "_x"})
+	check("1.2.3-rc..1", "1.2.3-rc", CoerceReport{Suffix: "..1"})
+	check("1.2.3-rc.1+b.2 (linux)", "1.2.3-rc.1+b.2", CoerceReport{Suffix: " (linux)"})
+	check("1.2-", "1.2.0", CoerceReport{Padded: true, Suffix: "-"})
+	check("1.2.", "1.2.0", CoerceReport{Padded: true, Suffix: "."})
+	check("1.0.0.Final", "1.0.0-Final", CoerceReport{ConvertedPrerelease: true})
+	check("release", "", CoerceReport{Prefix: "release"})
+	check("no digits", "", CoerceReport{Prefix: "no digits"})
+}
@@ -0,0 +1,142 @@
+//
+// Copyright 2018-2025 Cristian Maglie. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+
+package semver
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Major returns the major number of the version. If the number does not fit
+// in a uint64 math.MaxUint64 is returned, use MajorBig to get the exact value.
+func (v *Version) Major() uint64 {
+	major, _, _, _ := versionParts(v)
+	return toUint64(major)
+}
+
+// Minor returns the minor number of the version (0 if not specified). If the
+// number does not fit in a uint64 math.MaxUint64 is returned, use MinorBig to
+// get the exact value.
+func (v *Version) Minor() uint64 {
+	_, minor, _, _ := versionParts(v)
+	return toUint64(minor)
+}
+
+// Patch returns the patch number of the version (0 if not specified). If the
+// number does not fit in a uint64 math.MaxUint64 is returned, use PatchBig to
+// get the exact value.
+func (v *Version) Patch() uint64 {
+	_, _, patch, _ := versionParts(v)
+	return toUint64(patch)
+}
+
+// MajorBig returns the major number of the version
+func (v *Version) MajorBig() *big.Int {
+	major, _, _, _ := versionParts(v)
+	return toBigInt(major)
+}
+
+// MinorBig returns the minor number of the version (0 if not specified)
+func (v *Version) MinorBig() *big.Int {
+	_, minor, _, _ := versionParts(v)
+	return toBigInt(minor)
+}
+
+// PatchBig returns the patch number of the version (0 if not specified)
+func (v *Version) PatchBig() *big.Int {
+	_, _, patch, _ := versionParts(v)
+	return toBigInt(patch)
+}
+
+// PrereleaseIdentifier is one of the dot separated identifiers of the
+// pre-release part of a version, it may be numeric or alphanumeric.
+type PrereleaseIdentifier struct {
+	raw string
+}
+
+func (id PrereleaseIdentifier) String() string {
+	return id.raw
+}
+
+// IsNumeric returns true if the identifier is a number
+func (id PrereleaseIdentifier) IsNumeric() bool {
+	return isNumericIdentifier(id.raw)
+}
+
+// Uint64 returns the value of a numeric identifier. The second result is
+// false if the identifier is alphanumeric or if the number does not fit in
+// a uint64.
+func (id PrereleaseIdentifier) Uint64() (uint64, bool) {
+	if !id.IsNumeric() {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(id.raw, 10, 64)
+	return n, err == nil
+}
+
+// BigInt returns the value of a numeric identifier, or nil if the identifier
+// is alphanumeric.
+func (id PrereleaseIdentifier) BigInt() *big.Int {
+	if !id.IsNumeric() {
+		return nil
+	}
+	return toBigInt(id.raw)
+}
+
+// CompareTo compares the identifiers with the precedence rules of the
+// semver specification: numeric identifiers are compared numerically and
+// have lower precedence than alphanumeric identifiers, that are compared
+// lexically. It returns -1, 0 or 1.
+func (id PrereleaseIdentifier) CompareTo(other PrereleaseIdentifier) int {
+	numeric, otherNumeric := id.IsNumeric(), other.IsNumeric()
+	switch {
+	case numeric && otherNumeric:
+		return compareNumber([]byte(id.raw), []byte(other.raw))
+	case numeric:
+		return -1
+	case otherNumeric:
+		return 1
+	}
+	return compareAlpha([]byte(id.raw), []byte(other.raw))
+}
+
+// PrereleaseIdentifiers returns the identifiers of the pre-release part of
+// the version, or nil if the version is not a pre-release.
+func (v *Version) PrereleaseIdentifiers() []PrereleaseIdentifier {
+	if !v.IsPrerelease() {
+		return nil
+	}
+	var res []PrereleaseIdentifier
+	for _, id := range strings.Split(v.Prerelease(), ".") {
+		res = append(res, PrereleaseIdentifier{raw: id})
+	}
+	return res
+}
+
+// BuildIdentifiers returns the identifiers of the build metadata of the
+// version, or nil if the version has no build metadata.
+func (v *Version) BuildIdentifiers() []string {
+	if !v.HasBuildMetadata() {
+		return nil
+	}
+	return strings.Split(v.BuildMetadata(), ".")
+}
+
+func toUint64(n string) uint64 {
+	res, err := strconv.ParseUint(n, 10, 64)
+	if err != nil {
+		return math.MaxUint64
+	}
+	return res
+}
+
+func toBigInt(n string) *big.Int {
+	res, _ := new(big.Int).SetString(n, 10)
+	return res
+}
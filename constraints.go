@@ -7,8 +7,9 @@
 package semver
 
 import (
-	"fmt"
+	"slices"
 	"strings"
+	"unicode"
 )
 
 // Constraint is a condition that a Version can match or not
@@ -21,13 +22,37 @@ type Constraint interface {
 
 // ParseConstraint converts a string into a Constraint. The resulting Constraint
 // may be converted back to string using the String() method.
+//
+// If the string is not a valid Constraint a *ConstraintSyntaxError is returned.
 func ParseConstraint(in string) (Constraint, error) {
+	input := in
+	base := len(in) - len(strings.TrimLeftFunc(in, unicode.IsSpace))
 	in = strings.TrimSpace(in)
 	curr := 0
 	l := len(in)
 	if l == 0 {
 		return &True{}, nil
 	}
+	fail := func(pos int, expected ...string) error {
+		return &ConstraintSyntaxError{
+			Input:    input,
+			Offset:   base + pos,
+			Token:    tokenAt(in, pos),
+			Expected: expected,
+		}
+	}
+	parseVersion := func(start, end int) (*Version, error) {
+		v, err := Parse(in[start:end])
+		if err != nil {
+			return nil, &ConstraintSyntaxError{
+				Input:  input,
+				Offset: base + start,
+				Token:  in[start:end],
+				Err:    err,
+			}
+		}
+		return v, nil
+	}
 	next := func() byte {
 		if curr < l {
 			curr++
@@ -53,20 +78,65 @@ func ParseConstraint(in string) (Constraint, error) {
 			n := peek()
 			if !isIdentifier(n) && !isVersionSeparator(n) {
 				if start == curr {
-					return nil, fmt.Errorf("invalid version")
+					return nil, fail(curr, "version")
 				}
-				return Parse(in[start:curr])
+				return parseVersion(start, curr)
 			}
 			curr++
 		}
 	}
 
+	isWildcard := func(c byte) bool {
+		return c == 'x' || c == 'X' || c == '*'
+	}
+	xrange := func() (Constraint, error) {
+		start := curr
+		versionEnd := curr
+		wildcard := false
+		for components := 1; ; components++ {
+			if isWildcard(peek()) {
+				next()
+				wildcard = true
+			} else if !wildcard && isNumeric(peek()) {
+				for isNumeric(peek()) {
+					next()
+				}
+				versionEnd = curr
+			} else if wildcard {
+				return nil, fail(curr, "x", "X", "*")
+			} else {
+				return nil, fail(curr, "number", "x", "X", "*")
+			}
+			if components == 3 || peek() != '.' {
+				break
+			}
+			next()
+		}
+		if n := peek(); isIdentifier(n) || isVersionSeparator(n) {
+			return nil, fail(curr, endOfTerminalTokens...)
+		}
+		if !wildcard {
+			// A version without an operator
+			return nil, fail(start, operatorTokens...)
+		}
+		if versionEnd == start {
+			return &XRange{spelling: in[start:curr]}, nil
+		}
+		v, err := parseVersion(start, versionEnd)
+		if err != nil {
+			return nil, err
+		}
+		return &XRange{Version: v, spelling: in[start:curr]}, nil
+	}
+
 	var terminal func() (Constraint, error)
 	var constraint func() (Constraint, error)
 
 	terminal = func() (Constraint, error) {
 		skipSpace()
 		switch next() {
+		case 0:
+			return nil, fail(curr, terminalTokens...)
 		case '!':
 			expr, err := terminal()
 			if err != nil {
@@ -79,9 +149,10 @@ func ParseConstraint(in string) (Constraint, error) {
 				return nil, err
 			}
 			skipSpace()
-			if c := next(); c != ')' {
-				return nil, fmt.Errorf("unexpected char at: %s", in[curr-1:])
+			if peek() != ')' {
+				return nil, fail(curr, "&&", "||", ")")
 			}
+			next()
 			return expr, nil
 		case '=':
 			v, err := version()
@@ -95,6 +166,12 @@ func ParseConstraint(in string) (Constraint, error) {
 				return nil, err
 			}
 			return &CompatibleWith{v}, nil
+		case '~':
+			v, err := version()
+			if err != nil {
+				return nil, err
+			}
+			return &Tilde{v}, nil
 		case '>':
 			if peek() == '=' {
 				next()
@@ -126,7 +203,29 @@ func ParseConstraint(in string) (Constraint, error) {
 				return &LessThan{v}, nil
 			}
 		default:
-			return nil, fmt.Errorf("unexpected char at: %s", in[curr-1:])
+			if c := in[curr-1]; isNumeric(c) || isWildcard(c) {
+				curr--
+				start := curr
+				if lower, err := version(); err == nil {
+					// Check for an hyphen range "lower - upper"
+					end := curr
+					skipSpace()
+					if curr > end && peek() == '-' {
+						next()
+						if peek() == ' ' {
+							skipSpace()
+							upper, err := version()
+							if err != nil {
+								return nil, err
+							}
+							return &HyphenRange{Lower: lower, Upper: upper}, nil
+						}
+					}
+				}
+				curr = start
+				return xrange()
+			}
+			return nil, fail(curr-1, terminalTokens...)
 		}
 	}
 
@@ -147,7 +246,7 @@ func ParseConstraint(in string) (Constraint, error) {
 			}
 			next()
 			if peek() != '&' {
-				return nil, fmt.Errorf("unexpected char at: %s", in[curr-1:])
+				return nil, fail(curr-1, "&&")
 			}
 			next()
 
@@ -172,7 +271,7 @@ func ParseConstraint(in string) (Constraint, error) {
 			case '|':
 				next()
 				if peek() != '|' {
-					return nil, fmt.Errorf("unexpected char at: %s", in[curr-1:])
+					return nil, fail(curr-1, "||")
 				}
 				next()
 
@@ -189,12 +288,46 @@ func ParseConstraint(in string) (Constraint, error) {
 				return &Or{stack}, nil
 
 			default:
-				return nil, fmt.Errorf("unexpected char at: %s", in[curr-1:])
+				return nil, fail(curr, endOfTerminalTokens...)
 			}
 		}
 	}
 
-	return constraint()
+	res, err := constraint()
+	if err != nil {
+		return nil, err
+	}
+	if curr < l {
+		// Unbalanced closing parenthesis
+		return nil, fail(curr, "&&", "||")
+	}
+	return res, nil
+}
+
+// operatorTokens are the operators that may precede a version
+var operatorTokens = []string{"=", "^", "~", ">", ">=", "<", "<="}
+
+// terminalTokens are the tokens that may start a terminal constraint
+var terminalTokens = []string{"!", "(", "=", "^", "~", ">", ">=", "<", "<=", "version"}
+
+// endOfTerminalTokens are the tokens that may follow a terminal constraint
+var endOfTerminalTokens = []string{"&&", "||", ")"}
+
+// tokenAt returns the token starting at the position pos of in: a version
+// (or a part of it) or a single char. An empty string is returned at the
+// end of the input.
+func tokenAt(in string, pos int) string {
+	if pos >= len(in) {
+		return ""
+	}
+	end := pos
+	for end < len(in) && (isIdentifier(in[end]) || isVersionSeparator(in[end])) {
+		end++
+	}
+	if end == pos {
+		end++
+	}
+	return in[pos:end]
 }
 
 // True is the empty constraint
@@ -294,6 +427,101 @@ func (cw *CompatibleWith) String() string {
 	return "^" + cw.Version.String()
 }
 
+// Tilde is the "tilde" (~) constraint, it allows patch-level changes if the
+// minor version is specified, otherwise minor-level changes. For example:
+// "~1.2.3" matches ">=1.2.3 <1.3.0-0", "~1.2" matches ">=1.2.0 <1.3.0-0" and
+// "~1" matches ">=1.0.0 <2.0.0-0"
+type Tilde struct {
+	Version *Version
+}
+
+// Match returns true if v satisfies the condition
+func (t *Tilde) Match(v *Version) bool {
+	return v.GreaterThanOrEqual(t.Version) && v.LessThan(tildeUpperBound(t.Version))
+}
+
+func (t *Tilde) String() string {
+	return "~" + t.Version.String()
+}
+
+// HyphenRange is the inclusive range constraint "Lower - Upper". If the Upper
+// version is truncated all the versions starting with its numbers are
+// included, for example "1.2.3 - 2.3" is equivalent to ">=1.2.3 <2.4.0-0".
+type HyphenRange struct {
+	Lower *Version
+	Upper *Version
+}
+
+// Match returns true if v satisfies the condition
+func (h *HyphenRange) Match(v *Version) bool {
+	return v.GreaterThanOrEqual(h.Lower) && v.LessThan(hyphenUpperBound(h.Upper))
+}
+
+func (h *HyphenRange) String() string {
+	return h.Lower.String() + " - " + h.Upper.String()
+}
+
+// XRange is the wildcard constraint (like "1.2.x", "1.*" or "*"), it matches
+// all the versions starting with the specified numbers. For example: "1.2.x"
+// is equivalent to ">=1.2.0 <1.3.0-0", "1.x" to ">=1.0.0 <2.0.0-0" and "*"
+// matches any version.
+type XRange struct {
+	// Version contains the numbers specified before the wildcard, it is nil
+	// if no number is specified. Only the major and the minor numbers are
+	// used, the patch number, the pre-release and the build metadata are
+	// ignored: an XRange with Version "1.2.3" is "1.2.x".
+	Version *Version
+
+	spelling string
+}
+
+// Match returns true if v satisfies the condition
+func (x *XRange) Match(v *Version) bool {
+	if x.Version == nil {
+		return true
+	}
+	return v.GreaterThanOrEqual(x.numbers()) && v.LessThan(tildeUpperBound(x.Version))
+}
+
+// Range returns the equivalent range Constraint
+func (x *XRange) Range() Constraint {
+	if x.Version == nil {
+		return &True{}
+	}
+	return &And{[]Constraint{
+		&GreaterThanOrEqual{versionBound(x.numbers())},
+		&LessThan{tildeUpperBound(x.Version)},
+	}}
+}
+
+// String returns the constraint as it was written when parsed
+func (x *XRange) String() string {
+	if x.spelling != "" {
+		return x.spelling
+	}
+	if x.Version == nil {
+		return "*"
+	}
+	return x.prefix() + ".x"
+}
+
+// prefix returns the numbers of Version used by the X-range, like "1" or "1.2"
+func (x *XRange) prefix() string {
+	major, minor, _, _ := versionParts(x.Version)
+	if versionComponents(x.Version) == 1 {
+		return major
+	}
+	return major + "." + minor
+}
+
+// numbers returns the Version truncated to the numbers used by the X-range
+func (x *XRange) numbers() *Version {
+	if versionComponents(x.Version) < 3 && !x.Version.IsPrerelease() && !x.Version.HasBuildMetadata() {
+		return x.Version
+	}
+	return MustParse(x.prefix())
+}
+
 // Or will match if ANY of the Operands Constraint will match
 type Or struct {
 	Operands []Constraint
@@ -365,3 +593,72 @@ func (not *Not) String() string {
 	}
 	return "!" + op
 }
+
+// MatchContext holds the options used to match a Version against a Constraint
+type MatchContext struct {
+	// ExcludePrereleases enables the npm/Cargo policy for pre-releases: a
+	// pre-release version satisfies a Constraint only if the Constraint
+	// mentions a pre-release of the same major.minor.patch. For example
+	// ">=1.0.0-beta" matches "1.0.0-rc" but not "1.1.0-rc". Each operand of
+	// an Or is considered separately.
+	ExcludePrereleases bool
+}
+
+// Match returns true if v satisfies the Constraint c in this context. A nil
+// MatchContext is the default context where c.Match(v) is used.
+func (ctx *MatchContext) Match(c Constraint, v *Version) bool {
+	if ctx == nil || !ctx.ExcludePrereleases || !v.IsPrerelease() {
+		return c.Match(v)
+	}
+	return matchPrerelease(c, v)
+}
+
+func matchPrerelease(c Constraint, v *Version) bool {
+	if or, ok := c.(*Or); ok {
+		for _, op := range or.Operands {
+			if matchPrerelease(op, v) {
+				return true
+			}
+		}
+		return false
+	}
+	return c.Match(v) && mentionsPrerelease(c, v)
+}
+
+// mentionsPrerelease returns true if the constraint contains a pre-release
+// with the same major.minor.patch of v
+func mentionsPrerelease(c Constraint, v *Version) bool {
+	samePatch := func(u *Version) bool {
+		if !u.IsPrerelease() {
+			return false
+		}
+		uMajor, uMinor, uPatch, _ := versionParts(u)
+		vMajor, vMinor, vPatch, _ := versionParts(v)
+		return uMajor == vMajor && uMinor == vMinor && uPatch == vPatch
+	}
+	switch c := c.(type) {
+	case *Equals:
+		return samePatch(c.Version)
+	case *LessThan:
+		return samePatch(c.Version)
+	case *LessThanOrEqual:
+		return samePatch(c.Version)
+	case *GreaterThan:
+		return samePatch(c.Version)
+	case *GreaterThanOrEqual:
+		return samePatch(c.Version)
+	case *CompatibleWith:
+		return samePatch(c.Version)
+	case *Tilde:
+		return samePatch(c.Version)
+	case *HyphenRange:
+		return samePatch(c.Lower) || samePatch(c.Upper)
+	case *Not:
+		return mentionsPrerelease(c.Operand, v)
+	case *And:
+		return slices.ContainsFunc(c.Operands, func(op Constraint) bool { return mentionsPrerelease(op, v) })
+	case *Or:
+		return slices.ContainsFunc(c.Operands, func(op Constraint) bool { return mentionsPrerelease(op, v) })
+	}
+	return false
+}
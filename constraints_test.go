@@ -90,6 +90,89 @@ func TestConstraints(t *testing.T) {
 	require.True(t, comp.Match(v("1.4.5")))
 	require.True(t, comp.Match(v("1.4.5-rc.2")))
 	require.False(t, comp.Match(v("2.0.0")))
+
+	tilde := &Tilde{v("1.3.4-rc.3")}
+	require.False(t, tilde.Match(v("1.3.2")))
+	require.False(t, tilde.Match(v("1.3.4-rc.1")))
+	require.True(t, tilde.Match(v("1.3.4-rc.5")))
+	require.True(t, tilde.Match(v("1.3.4")))
+	require.True(t, tilde.Match(v("1.3.6")))
+	require.False(t, tilde.Match(v("1.4.0-0")))
+	require.False(t, tilde.Match(v("1.4.0")))
+	require.Equal(t, "~1.3.4-rc.3", tilde.String())
+
+	tildeMinor := &Tilde{v("1.3")}
+	require.False(t, tildeMinor.Match(v("1.2.9")))
+	require.True(t, tildeMinor.Match(v("1.3.0")))
+	require.True(t, tildeMinor.Match(v("1.3.9")))
+	require.False(t, tildeMinor.Match(v("1.4.0")))
+	require.Equal(t, "~1.3", tildeMinor.String())
+
+	tildeMajor := &Tilde{v("1")}
+	require.False(t, tildeMajor.Match(v("0.9.0")))
+	require.True(t, tildeMajor.Match(v("1.0.0")))
+	require.True(t, tildeMajor.Match(v("1.9.9")))
+	require.False(t, tildeMajor.Match(v("2.0.0-rc")))
+	require.False(t, tildeMajor.Match(v("2.0.0")))
+
+	xrange, err := ParseConstraint("1.2.x")
+	require.NoError(t, err)
+	require.False(t, xrange.Match(v("1.1.9")))
+	require.False(t, xrange.Match(v("1.2.0-rc")))
+	require.True(t, xrange.Match(v("1.2.0")))
+	require.True(t, xrange.Match(v("1.2.9")))
+	require.False(t, xrange.Match(v("1.3.0-0")))
+	require.Equal(t, "(>=1.2.0 && <1.3.0-0)", xrange.(*XRange).Range().String())
+	require.Equal(t, "1.2.x", (&XRange{Version: v("1.2")}).String())
+	require.Equal(t, "*", (&XRange{}).String())
+
+	// Only the major and minor numbers of a full version are used
+	full := &XRange{Version: v("1.2.3-rc+build")}
+	require.Equal(t, "1.2.x", full.String())
+	require.True(t, full.Match(v("1.2.0")))
+	require.True(t, full.Match(v("1.2.9")))
+	require.False(t, full.Match(v("1.3.0-0")))
+	require.Equal(t, "(>=1.2.0 && <1.3.0-0)", full.Range().String())
+	parsed, err := ParseConstraint(full.String())
+	require.NoError(t, err)
+	require.Equal(t, full.Range().String(), parsed.(*XRange).Range().String())
+
+	xrangeMajor, err := ParseConstraint("1.*")
+	require.NoError(t, err)
+	require.False(t, xrangeMajor.Match(v("0.9.0")))
+	require.True(t, xrangeMajor.Match(v("1.0.0")))
+	require.True(t, xrangeMajor.Match(v("1.9.9")))
+	require.False(t, xrangeMajor.Match(v("2.0.0")))
+	require.Equal(t, "(>=1.0.0 && <2.0.0-0)", xrangeMajor.(*XRange).Range().String())
+
+	xrangeAny, err := ParseConstraint("*")
+	require.NoError(t, err)
+	require.True(t, xrangeAny.Match(v("0.0.0-0")))
+	require.True(t, xrangeAny.Match(v("2.0.0")))
+	require.Equal(t, "", xrangeAny.(*XRange).Range().String())
+
+	hyphen := &HyphenRange{v("1.2.3"), v("2.3.4")}
+	require.False(t, hyphen.Match(v("1.2.2")))
+	require.True(t, hyphen.Match(v("1.2.3")))
+	require.True(t, hyphen.Match(v("2.3.4")))
+	require.False(t, hyphen.Match(v("2.3.5-0")))
+	require.Equal(t, "1.2.3 - 2.3.4", hyphen.String())
+	hyphenMinor := &HyphenRange{v("1.2"), v("2.3")}
+	require.False(t, hyphenMinor.Match(v("1.2.0-rc")))
+	require.True(t, hyphenMinor.Match(v("1.2.0")))
+	require.True(t, hyphenMinor.Match(v("2.3.9")))
+	require.False(t, hyphenMinor.Match(v("2.4.0-0")))
+	hyphenMajor := &HyphenRange{v("1.2.3"), v("2")}
+	require.True(t, hyphenMajor.Match(v("2.9.9")))
+	require.False(t, hyphenMajor.Match(v("3.0.0-0")))
+	hyphenPrerelease := &HyphenRange{v("1.2.3"), v("2.0.0-rc")}
+	require.True(t, hyphenPrerelease.Match(v("2.0.0-rc")))
+	require.False(t, hyphenPrerelease.Match(v("2.0.0-rc.1")))
+
+	tildeZero := &Tilde{v("0.2.3")}
+	require.False(t, tildeZero.Match(v("0.2.2")))
+	require.True(t, tildeZero.Match(v("0.2.9")))
+	require.False(t, tildeZero.Match(v("0.3.0")))
 }
 
 func TestConstraintsParser(t *testing.T) {
@@ -110,6 +193,30 @@ func TestConstraintsParser(t *testing.T) {
 		{" ^1.3.0", "^1.3.0"},
 		{"^1.3.0 ", "^1.3.0"},
 		{" ^1.3.0 ", "^1.3.0"},
+		{"~1.3.0", "~1.3.0"},
+		{"~1.3", "~1.3"},
+		{"~1", "~1"},
+		{"~1.3.0-rc.1", "~1.3.0-rc.1"},
+		{"~1.3.0 || ~2.1", "(~1.3.0 || ~2.1)"},
+		{"!~1.3.0", "!(~1.3.0)"},
+		{"1.2.x", "1.2.x"},
+		{"1.2.X", "1.2.X"},
+		{"1.2.*", "1.2.*"},
+		{"1.x", "1.x"},
+		{"1.*", "1.*"},
+		{"1.x.x", "1.x.x"},
+		{"1.*.*", "1.*.*"},
+		{"x", "x"},
+		{"*", "*"},
+		{"x.x.x", "x.x.x"},
+		{"1.2.x || 2.*", "(1.2.x || 2.*)"},
+		{"!1.x && >=1.5.0", "(!(1.x) && >=1.5.0)"},
+		{"1.2.3 - 2.3.4", "1.2.3 - 2.3.4"},
+		{"1.2.3  -  2.3", "1.2.3 - 2.3"},
+		{"1.2 - 2", "1.2 - 2"},
+		{"1.2.3-rc.1 - 2.0.0-beta", "1.2.3-rc.1 - 2.0.0-beta"},
+		{"1.0.0 - 1.5.0 || 2.0.0 - 3", "(1.0.0 - 1.5.0 || 2.0.0 - 3)"},
+		{"!(1.0.0 - 1.5.0) && >=1.2.0", "(!(1.0.0 - 1.5.0) && >=1.2.0)"},
 		{"(=1.4.0)", "=1.4.0"},
 		{"!(=1.4.0)", "!(=1.4.0)"},
 		{"!(((=1.4.0)))", "!(=1.4.0)"},
@@ -144,6 +251,24 @@ func TestConstraintsParser(t *testing.T) {
 		">1.0.0 =2.0.0",
 		">1.0.0 &",
 		"^1.1.1.1",
+		"~",
+		"~ 1.0.0",
+		"~~1.0.0",
+		"~1.1.1.1",
+		"1.2.3.x",
+		"1.x.3",
+		"1.2.x-rc",
+		"1.2.xx",
+		"1.2x",
+		"x.",
+		"01.x",
+		"=1.x",
+		"1.2.3 -2.3.4",
+		"1.2.3- 2.3.4",
+		"1.2.3 - ",
+		"1.2.3 - 2.x",
+		"1.2.3 - >2.0.0",
+		"1.2.3 - 2.3.4 - 3.0.0",
 		"!1.0.0",
 		">1.0.0 && 2.0.0",
 		">1.0.0 | =2.0.0",
@@ -161,3 +286,81 @@ func TestConstraintsParser(t *testing.T) {
 		})
 	}
 }
+
+func TestMatchContext(t *testing.T) {
+	ctx := &MatchContext{ExcludePrereleases: true}
+	match := func(constraint, version string) bool {
+		c, err := ParseConstraint(constraint)
+		require.NoError(t, err)
+		return ctx.Match(c, v(version))
+	}
+	require.True(t, match(">=1.0.0", "2.0.0"))
+	require.False(t, match(">=1.0.0", "2.0.0-beta"))
+	require.True(t, match(">=1.0.0-beta", "1.0.0-rc"))
+	require.True(t, match(">=1.0.0-beta", "1.1.0"))
+	require.False(t, match(">=1.0.0-beta", "1.1.0-rc"))
+	require.False(t, match(">=1.0.0-beta", "1.0.0-alpha"))
+	require.True(t, match("^1.2.3-alpha", "1.2.3-beta"))
+	require.False(t, match("^1.2.3-alpha", "1.2.4-beta"))
+	require.True(t, match("=1.2.3-alpha", "1.2.3-alpha"))
+	require.True(t, match("(>=1.0.0-rc && <2.0.0) || >=3.0.0", "1.0.0-rc.1"))
+	require.False(t, match("(>=1.0.0-rc && <2.0.0) || >=3.0.0", "3.0.0-rc"))
+	require.False(t, match(">=1.0.0-rc || >=3.0.0", "3.0.0-rc"))
+	require.True(t, match(">=1.0.0 && <2.0.0-rc", "2.0.0-beta"))
+	require.True(t, match("!(=1.0.0-rc)", "1.0.0-beta"))
+	require.False(t, match("!(=1.0.0-rc)", "1.1.0-beta"))
+	require.False(t, ctx.Match(&customConstraint{}, v("1.0.0-rc")))
+
+	// Without the policy the Constraint.Match is used
+	c, err := ParseConstraint(">=1.0.0")
+	require.NoError(t, err)
+	require.True(t, (*MatchContext)(nil).Match(c, v("2.0.0-beta")))
+	require.True(t, (&MatchContext{}).Match(c, v("2.0.0-beta")))
+}
+
+func TestConstraintSyntaxError(t *testing.T) {
+	tests := []struct {
+		in       string
+		offset   int
+		token    string
+		expected []string
+	}{
+		{">1.0.0 &", 7, "&", []string{"&&"}},
+		{"  >1.0.0 | =2.0.0", 9, "|", []string{"||"}},
+		{">1.0.0 && 2.0.0", 10, "2.0.0", operatorTokens},
+		{"(>1.0.0 || =2.0.0", 17, "", []string{"&&", "||", ")"}},
+		{">1.0.0)", 6, ")", []string{"&&", "||"}},
+		{">1.0.0) anything", 6, ")", []string{"&&", "||"}},
+		{"(=1.0.0)) || >2.0.0", 8, ")", []string{"&&", "||"}},
+		{"1.2.xx", 5, "x", endOfTerminalTokens},
+		{"1.x.3", 4, "3", []string{"x", "X", "*"}},
+		{"~ 1.0.0", 1, " ", []string{"version"}},
+		{">1.0.0 && ", 9, "", terminalTokens},
+	}
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			_, err := ParseConstraint(test.in)
+			var syntaxErr *ConstraintSyntaxError
+			require.ErrorAs(t, err, &syntaxErr)
+			require.Equal(t, test.in, syntaxErr.Input)
+			require.Equal(t, test.offset, syntaxErr.Offset)
+			require.Equal(t, test.token, syntaxErr.Token)
+			require.Equal(t, test.expected, syntaxErr.Expected)
+			require.NoError(t, syntaxErr.Unwrap())
+		})
+	}
+
+	_, err := ParseConstraint(">1.0.0 || ^1.1.1.1")
+	var syntaxErr *ConstraintSyntaxError
+	require.ErrorAs(t, err, &syntaxErr)
+	require.Equal(t, 11, syntaxErr.Offset)
+	require.Equal(t, "1.1.1.1", syntaxErr.Token)
+	require.Error(t, syntaxErr.Err)
+	require.ErrorIs(t, err, syntaxErr.Err)
+	require.EqualError(t, err, "invalid version '1.1.1.1' at offset 11: invalid patch version separator '.'")
+
+	_, err = ParseConstraint(">1.0.0 &")
+	require.EqualError(t, err, "unexpected '&' at offset 7, expected '&&'")
+	_, err = ParseConstraint("(>1.0.0")
+	require.EqualError(t, err, "unexpected end of constraint at offset 7, expected '&&' or '||' or ')'")
+}
@@ -0,0 +1,561 @@
+//
+// Copyright 2018-2025 Cristian Maglie. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+
+package semver
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Dialect is the syntax used to write version constraints
+type Dialect int
+
+const (
+	// DialectNative is the syntax of ParseConstraint
+	DialectNative Dialect = iota
+	// DialectNPM is the syntax used by npm: comparators separated by spaces
+	// are in AND, ranges separated by "||" are in OR. A bare version is an
+	// exact match (or an X-range if truncated).
+	DialectNPM
+	// DialectCargo is the syntax used by Cargo: comparators separated by
+	// commas are in AND. A bare version is a caret (^) requirement.
+	DialectCargo
+	// DialectPEP440 is the syntax of the version specifiers of Python (PEP 440):
+	// clauses separated by commas are in AND. The versions must be valid
+	// semantic versions.
+	DialectPEP440
+	// DialectComposer is the syntax used by Composer: comparators separated by
+	// commas or spaces are in AND, constraints separated by "||" are in OR.
+	DialectComposer
+)
+
+func (d Dialect) String() string {
+	switch d {
+	case DialectNative:
+		return "native"
+	case DialectNPM:
+		return "npm"
+	case DialectCargo:
+		return "cargo"
+	case DialectPEP440:
+		return "pep440"
+	case DialectComposer:
+		return "composer"
+	}
+	return fmt.Sprintf("Dialect(%d)", int(d))
+}
+
+var dialectOperators = map[Dialect][]string{
+	DialectNPM:      {"<=", ">=", "<", ">", "=", "~", "^"},
+	DialectCargo:    {"<=", ">=", "<", ">", "=", "~", "^"},
+	DialectPEP440:   {"==", "!=", "~=", "<=", ">=", "<", ">"},
+	DialectComposer: {"==", "!=", "<>", "<=", ">=", "<", ">", "=", "~", "^"},
+}
+
+// ParseConstraintWithDialect converts a string written with the syntax of the
+// given Dialect into a Constraint. The Constraint may be converted back into
+// the same Dialect with FormatConstraint.
+func ParseConstraintWithDialect(in string, dialect Dialect) (Constraint, error) {
+	if dialect == DialectNative {
+		return ParseConstraint(in)
+	}
+	if _, ok := dialectOperators[dialect]; !ok {
+		return nil, fmt.Errorf("unknown dialect %s", dialect)
+	}
+	in = strings.TrimSpace(in)
+	if in == "" {
+		return &True{}, nil
+	}
+
+	var alternatives []string
+	switch dialect {
+	case DialectNPM:
+		alternatives = strings.Split(in, "||")
+	case DialectComposer:
+		alternatives = strings.Split(strings.ReplaceAll(in, "||", "|"), "|")
+	default:
+		if strings.Contains(in, "|") {
+			return nil, fmt.Errorf("OR is not supported by the %s dialect", dialect)
+		}
+		alternatives = []string{in}
+	}
+
+	var or []Constraint
+	for _, alt := range alternatives {
+		c, err := parseDialectRange(strings.TrimSpace(alt), dialect)
+		if err != nil {
+			return nil, err
+		}
+		or = append(or, c)
+	}
+	if len(or) == 1 {
+		return or[0], nil
+	}
+	return &Or{or}, nil
+}
+
+// parseDialectRange parses a set of comparators in AND
+func parseDialectRange(in string, dialect Dialect) (Constraint, error) {
+	if in == "" {
+		if dialect == DialectNPM {
+			return &True{}, nil
+		}
+		return nil, fmt.Errorf("empty constraint")
+	}
+	if dialect == DialectNPM || dialect == DialectComposer {
+		if lower, upper, found := strings.Cut(in, " - "); found {
+			l, err := parseDialectHyphenVersion(strings.TrimSpace(lower), dialect)
+			if err != nil {
+				return nil, err
+			}
+			u, err := parseDialectHyphenVersion(strings.TrimSpace(upper), dialect)
+			if err != nil {
+				return nil, err
+			}
+			return &HyphenRange{Lower: l, Upper: u}, nil
+		}
+	}
+
+	var tokens []string
+	switch dialect {
+	case DialectNPM:
+		tokens = strings.Fields(in)
+	case DialectComposer:
+		for _, token := range strings.Split(in, ",") {
+			if strings.TrimSpace(token) == "" {
+				return nil, fmt.Errorf("empty constraint")
+			}
+			tokens = append(tokens, strings.Fields(token)...)
+		}
+	default:
+		for _, token := range strings.Split(in, ",") {
+			// Spaces between the operator and the version are allowed
+			tokens = append(tokens, strings.Join(strings.Fields(token), ""))
+		}
+	}
+
+	var and []Constraint
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		if i+1 < len(tokens) && isDialectOperator(token, dialect) {
+			// The operator is separated from the version by spaces
+			i++
+			token += tokens[i]
+		}
+		c, err := parseDialectComparator(token, dialect)
+		if err != nil {
+			return nil, err
+		}
+		and = append(and, c)
+	}
+	if len(and) == 1 {
+		return and[0], nil
+	}
+	return &And{and}, nil
+}
+
+func isDialectOperator(in string, dialect Dialect) bool {
+	for _, op := range dialectOperators[dialect] {
+		if in == op {
+			return true
+		}
+	}
+	return false
+}
+
+// isDialectXRange returns true if the version contains wildcards
+func isDialectXRange(in string) bool {
+	return strings.ContainsAny(in, "xX*") && !strings.ContainsAny(in, "-+")
+}
+
+// parseDialectXRange parses a version with wildcards, like "1.2.x" or "*"
+func parseDialectXRange(in string, dialect Dialect) (*XRange, error) {
+	if dialect == DialectNPM || dialect == DialectComposer {
+		in = strings.TrimPrefix(strings.TrimPrefix(in, "v"), "V")
+	}
+	c, err := ParseConstraint(in)
+	if err != nil {
+		return nil, err
+	}
+	xrange, ok := c.(*XRange)
+	if !ok {
+		return nil, fmt.Errorf("invalid version: %s", in)
+	}
+	return xrange, nil
+}
+
+// parseDialectHyphenVersion parses a bound of an hyphen range, an X-range
+// is the truncated version, so "1.2.3 - 2.x" is "1.2.3 - 2"
+func parseDialectHyphenVersion(in string, dialect Dialect) (*Version, error) {
+	if !isDialectXRange(in) {
+		return parseDialectVersion(in, dialect)
+	}
+	xrange, err := parseDialectXRange(in, dialect)
+	if err != nil {
+		return nil, err
+	}
+	if xrange.Version == nil {
+		return nil, fmt.Errorf("invalid hyphen range bound: %s", in)
+	}
+	return xrange.Version, nil
+}
+
+// parseDialectVersion parses a version, in the npm and Composer dialects the
+// version may be prefixed by "v"
+func parseDialectVersion(in string, dialect Dialect) (*Version, error) {
+	if dialect == DialectNPM || dialect == DialectComposer {
+		in = strings.TrimPrefix(strings.TrimPrefix(in, "v"), "V")
+	}
+	if in == "" {
+		return nil, fmt.Errorf("missing version")
+	}
+	v, err := Parse(in)
+	if err != nil {
+		return nil, err
+	}
+	if versionComponents(v) < 3 && v.HasBuildMetadata() {
+		return nil, fmt.Errorf("build metadata is not allowed in a partial version: %s", in)
+	}
+	return v, nil
+}
+
+func parseDialectComparator(in string, dialect Dialect) (Constraint, error) {
+	op := ""
+	for _, candidate := range dialectOperators[dialect] {
+		if strings.HasPrefix(in, candidate) {
+			op = candidate
+			break
+		}
+	}
+	verString := in[len(op):]
+	var v *Version
+	if isDialectXRange(verString) {
+		xrange, err := parseDialectXRange(verString, dialect)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case op == "" || op == "=" || op == "==":
+			return xrange, nil
+		case op == "!=":
+			return &Not{xrange}, nil
+		case dialect == DialectPEP440:
+			return nil, fmt.Errorf("operator %s is not supported with wildcards", op)
+		case xrange.Version == nil && (op == ">=" || op == "^" || op == "~"):
+			return xrange, nil
+		case xrange.Version == nil:
+			return nil, fmt.Errorf("operator %s is not supported with wildcards", op)
+		}
+		// Under an operator the X-range is the truncated version, so
+		// "^1.2.x" is "^1.2"
+		v = xrange.Version
+	} else {
+		var err error
+		if v, err = parseDialectVersion(verString, dialect); err != nil {
+			return nil, err
+		}
+	}
+	partial := versionComponents(v) < 3 && !v.IsPrerelease()
+	if dialect == DialectNPM || dialect == DialectCargo {
+		// In npm and Cargo the missing numbers of a partial version behave
+		// like a wildcard
+		switch {
+		case op == ">" && partial:
+			// The pre-releases of the next version are not greater than
+			// the partial version, so ">1.2" is ">=1.3.0"
+			major, minor, patch, _ := versionParts(tildeUpperBound(v))
+			return &GreaterThanOrEqual{buildVersion(major, minor, patch, "")}, nil
+		case op == "<=" && partial:
+			return &LessThan{tildeUpperBound(v)}, nil
+		case op == "=" && partial, op == "" && partial && dialect == DialectNPM:
+			return &XRange{Version: v}, nil
+		case op == "^", op == "" && dialect == DialectCargo:
+			return dialectCaret(v), nil
+		case op == "":
+			return &Equals{v}, nil
+		}
+	}
+	switch op {
+	case "", "=", "==":
+		if op == "" && dialect == DialectPEP440 {
+			return nil, fmt.Errorf("missing operator: %s", in)
+		}
+		return &Equals{v}, nil
+	case "!=", "<>":
+		return &Not{&Equals{v}}, nil
+	case "<":
+		if dialect == DialectPEP440 && !v.IsPrerelease() {
+			// "<2.0" excludes the pre-releases of 2.0 in PEP 440
+			major, minor, patch, _ := versionParts(v)
+			return &LessThan{buildVersion(major, minor, patch, "0")}, nil
+		}
+		return &LessThan{v}, nil
+	case "<=":
+		return &LessThanOrEqual{v}, nil
+	case ">":
+		return &GreaterThan{v}, nil
+	case ">=":
+		return &GreaterThanOrEqual{v}, nil
+	case "^":
+		return &CompatibleWith{v}, nil
+	case "~":
+		if dialect == DialectComposer && versionComponents(v) < 3 {
+			// In Composer "~1.2" is equivalent to ">=1.2 <2.0.0"
+			return compatibleRelease(v), nil
+		}
+		return &Tilde{v}, nil
+	case "~=":
+		if versionComponents(v) < 2 {
+			return nil, fmt.Errorf("invalid compatible release with a single number: %s", in)
+		}
+		return compatibleRelease(v), nil
+	}
+	return nil, fmt.Errorf("unsupported operator %s", op)
+}
+
+// dialectCaret returns the Constraint matching the caret range of npm and
+// Cargo. The missing numbers of a partial version are not considered when
+// looking for the first non-zero number, so "^0" is "<1.0.0" and "^0.0" is
+// "<0.1.0", while the native "^0" matches only 0.0.x.
+func dialectCaret(v *Version) Constraint {
+	major, minor, _, _ := versionParts(v)
+	if versionComponents(v) == 3 || major != "0" || (versionComponents(v) == 2 && minor != "0") {
+		return &CompatibleWith{v}
+	}
+	return &And{[]Constraint{&GreaterThanOrEqual{v}, &LessThan{tildeUpperBound(v)}}}
+}
+
+// compatibleRelease returns the Constraint matching the versions greater than
+// or equal to v where only the last specified number may change.
+func compatibleRelease(v *Version) Constraint {
+	if versionComponents(v) == 3 {
+		return &Tilde{v}
+	}
+	prefix := v
+	if versionComponents(v) == 2 {
+		major, _, _, _ := versionParts(v)
+		prefix = MustParse(major)
+	}
+	return &And{[]Constraint{&GreaterThanOrEqual{v}, &XRange{Version: prefix}}}
+}
+
+// FormatConstraint converts the Constraint into a string with the syntax of
+// the given Dialect. An error is returned if the Constraint can not be
+// expressed in the Dialect.
+func FormatConstraint(c Constraint, dialect Dialect) (string, error) {
+	if dialect == DialectNative {
+		return c.String(), nil
+	}
+	if _, ok := dialectOperators[dialect]; !ok {
+		return "", fmt.Errorf("unknown dialect %s", dialect)
+	}
+	if res, ok := formatDialectOr(c, dialect); ok {
+		return res, nil
+	}
+	// Fallback to the ranges of versions matching the constraint
+	set, ok := constraintToVersionSet(c)
+	if !ok {
+		return "", fmt.Errorf("constraint %s can not be expressed in the %s dialect", c, dialect)
+	}
+	if len(set) == 0 {
+		return formatDialectComparator(&LessThan{minVersion}, dialect)
+	}
+	if len(set) > 1 && (dialect == DialectCargo || dialect == DialectPEP440) {
+		return "", fmt.Errorf("constraint %s can not be expressed in the %s dialect", c, dialect)
+	}
+	var alternatives []string
+	for _, in := range set {
+		var and []Constraint
+		if in.lower != nil {
+			if in.upper != nil && in.upper.Equal(successor(in.lower)) {
+				and = append(and, &Equals{in.lower})
+			} else if p := predecessor(in.lower); p != nil {
+				and = append(and, &GreaterThan{p})
+			} else {
+				and = append(and, &GreaterThanOrEqual{in.lower})
+			}
+		}
+		if in.upper != nil && (len(and) == 0 || !isEquals(and[0])) {
+			if p := predecessor(in.upper); p != nil {
+				and = append(and, &LessThanOrEqual{p})
+			} else {
+				and = append(and, &LessThan{in.upper})
+			}
+		}
+		res, ok := formatDialectAnd(&And{and}, dialect)
+		if !ok {
+			return "", fmt.Errorf("constraint %s can not be expressed in the %s dialect", c, dialect)
+		}
+		alternatives = append(alternatives, res)
+	}
+	return strings.Join(alternatives, " || "), nil
+}
+
+func isEquals(c Constraint) bool {
+	_, ok := c.(*Equals)
+	return ok
+}
+
+func formatDialectOr(c Constraint, dialect Dialect) (string, bool) {
+	or, ok := c.(*Or)
+	if !ok {
+		return formatDialectAnd(c, dialect)
+	}
+	if dialect == DialectCargo || dialect == DialectPEP440 {
+		return "", false
+	}
+	var res []string
+	for _, op := range or.Operands {
+		s, ok := formatDialectOr(op, dialect)
+		if !ok {
+			return "", false
+		}
+		res = append(res, s)
+	}
+	return strings.Join(res, " || "), true
+}
+
+func formatDialectAnd(c Constraint, dialect Dialect) (string, bool) {
+	var comparators []string
+	var visit func(c Constraint) bool
+	visit = func(c Constraint) bool {
+		switch c := c.(type) {
+		case *And:
+			for _, op := range c.Operands {
+				if !visit(op) {
+					return false
+				}
+			}
+			return true
+		case *True:
+			// Always satisfied, it can be omitted
+			return true
+		}
+		s, err := formatDialectComparator(c, dialect)
+		if err != nil {
+			return false
+		}
+		comparators = append(comparators, s)
+		return true
+	}
+	if !visit(c) {
+		return "", false
+	}
+	if len(comparators) == 0 {
+		if dialect == DialectPEP440 {
+			return "", true
+		}
+		return "*", true
+	}
+	if dialect == DialectNPM {
+		return strings.Join(comparators, " "), true
+	}
+	return strings.Join(comparators, ", "), true
+}
+
+func formatDialectComparator(c Constraint, dialect Dialect) (string, error) {
+	unsupported := fmt.Errorf("constraint %s can not be expressed in the %s dialect", c, dialect)
+	version := func(v *Version) string {
+		if dialect == DialectNPM || dialect == DialectCargo {
+			// Truncated versions have a different meaning
+			return string(v.NormalizedString())
+		}
+		return v.String()
+	}
+	switch c := c.(type) {
+	case *Equals:
+		switch dialect {
+		case DialectNPM:
+			return version(c.Version), nil
+		case DialectPEP440:
+			return "==" + version(c.Version), nil
+		}
+		return "=" + version(c.Version), nil
+	case *LessThan:
+		if dialect == DialectPEP440 && c.Version.Prerelease() == "0" {
+			// "<2.0.0" excludes the pre-releases of 2.0.0 in PEP 440
+			major, minor, patch, _ := versionParts(c.Version)
+			return "<" + major + "." + minor + "." + patch, nil
+		}
+		if dialect == DialectPEP440 && !c.Version.IsPrerelease() {
+			// The pre-releases of the version can not be included
+			return "", unsupported
+		}
+		return "<" + version(c.Version), nil
+	case *LessThanOrEqual:
+		return "<=" + version(c.Version), nil
+	case *GreaterThan:
+		return ">" + version(c.Version), nil
+	case *GreaterThanOrEqual:
+		return ">=" + version(c.Version), nil
+	case *CompatibleWith:
+		if dialect == DialectPEP440 || versionComponents(c.Version) < 3 {
+			// The caret of a partial version has a different meaning in the
+			// other dialects, like "^0" that is "<1.0.0" in npm
+			return formatDialectRange(c.Version, caretUpperBound(c.Version), dialect)
+		}
+		return "^" + c.Version.String(), nil
+	case *Tilde:
+		switch dialect {
+		case DialectPEP440:
+			major, minor, patch, _ := versionParts(c.Version)
+			if versionComponents(c.Version) == 1 || c.Version.IsPrerelease() {
+				return formatDialectRange(c.Version, tildeUpperBound(c.Version), dialect)
+			}
+			return "~=" + major + "." + minor + "." + patch, nil
+		case DialectComposer:
+			if versionComponents(c.Version) < 3 {
+				return c.Version.String() + ".*", nil
+			}
+		}
+		return "~" + c.Version.String(), nil
+	case *XRange:
+		if dialect == DialectPEP440 {
+			if c.Version == nil {
+				return "", unsupported
+			}
+			return "==" + c.prefix() + ".*", nil
+		}
+		if c.Version == nil {
+			return "*", nil
+		}
+		return c.prefix() + ".*", nil
+	case *HyphenRange:
+		if dialect == DialectNPM || dialect == DialectComposer {
+			return c.String(), nil
+		}
+		return formatDialectRange(c.Lower, hyphenUpperBound(c.Upper), dialect)
+	case *Not:
+		if dialect == DialectPEP440 || dialect == DialectComposer {
+			switch op := c.Operand.(type) {
+			case *Equals:
+				return "!=" + op.Version.String(), nil
+			case *XRange:
+				if op.Version != nil && dialect == DialectPEP440 {
+					return "!=" + op.prefix() + ".*", nil
+				}
+			}
+		}
+	}
+	return "", unsupported
+}
+
+// formatDialectRange formats the range [lower, upper)
+func formatDialectRange(lower, upper *Version, dialect Dialect) (string, error) {
+	lowerString, err := formatDialectComparator(&GreaterThanOrEqual{lower}, dialect)
+	if err != nil {
+		return "", err
+	}
+	upperString, err := formatDialectComparator(&LessThan{upper}, dialect)
+	if err != nil {
+		return "", err
+	}
+	if dialect == DialectNPM {
+		return lowerString + " " + upperString, nil
+	}
+	return lowerString + ", " + upperString, nil
+}
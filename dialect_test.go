@@ -0,0 +1,221 @@
+//
+// Copyright 2018-2025 Cristian Maglie. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+
+package semver
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseConstraintWithDialect(t *testing.T) {
+	good := []struct {
+		in      string
+		dialect Dialect
+		native  string
+	}{
+		{">=1.2.0 && <2.0.0", DialectNative, "(>=1.2.0 && <2.0.0)"},
+		{"", DialectNPM, ""},
+		{"*", DialectNPM, "*"},
+		{"1.2.3", DialectNPM, "=1.2.3"},
+		{"v1.2.3", DialectNPM, "=1.2.3"},
+		{"1.2", DialectNPM, "1.2.x"},
+		{"^1.2.3", DialectNPM, "^1.2.3"},
+		{"~1.2", DialectNPM, "~1.2"},
+		{">= 1.2.0 < 2", DialectNPM, "(>=1.2.0 && <2)"},
+		{"^1.2.x", DialectNPM, "^1.2"},
+		{"~1.2.x", DialectNPM, "~1.2"},
+		{">=1.2.x", DialectNPM, ">=1.2"},
+		{">1.*", DialectNPM, ">=2.0.0"},
+		{"<1.2.X", DialectNPM, "<1.2"},
+		{">=*", DialectNPM, "*"},
+		{"^0.x", DialectNPM, "(>=0 && <1.0.0-0)"},
+		{"1.2.3 - 2.x", DialectNPM, "1.2.3 - 2"},
+		{"1.x - 2.x", DialectComposer, "1 - 2"},
+		{"^1.2.*", DialectComposer, "^1.2"},
+		{"^0", DialectNPM, "(>=0 && <1.0.0-0)"},
+		{"^0.0", DialectNPM, "(>=0.0 && <0.1.0-0)"},
+		{"^0.1", DialectNPM, "^0.1"},
+		{"^1", DialectNPM, "^1"},
+		{">1.2", DialectNPM, ">=1.3.0"},
+		{"<=1.2", DialectNPM, "<1.3.0-0"},
+		{"1.x || >=2.5.0 || 5.0.0 - 7.2.3", DialectNPM, "(1.x || >=2.5.0 || 5.0.0 - 7.2.3)"},
+		{"1.2.3", DialectCargo, "^1.2.3"},
+		{"1.2", DialectCargo, "^1.2"},
+		{"=1.2", DialectCargo, "1.2.x"},
+		{"0", DialectCargo, "(>=0 && <1.0.0-0)"},
+		{"0.0", DialectCargo, "(>=0.0 && <0.1.0-0)"},
+		{"^0.0", DialectCargo, "(>=0.0 && <0.1.0-0)"},
+		{"^0.0.3", DialectCargo, "^0.0.3"},
+		{"~1.2.3", DialectCargo, "~1.2.3"},
+		{"1.*", DialectCargo, "1.*"},
+		{">= 1.2.0, < 1.5", DialectCargo, "(>=1.2.0 && <1.5)"},
+		{"~=1.4.5", DialectPEP440, "~1.4.5"},
+		{"~=2.2", DialectPEP440, "(>=2.2 && 2.x)"},
+		{"==1.2.*", DialectPEP440, "1.2.*"},
+		{"!=1.2.*", DialectPEP440, "!(1.2.*)"},
+		{">=1.0, !=1.3.4, <2.0", DialectPEP440, "(>=1.0 && !(=1.3.4) && <2.0.0-0)"},
+		{"<2.0.0-rc.1", DialectPEP440, "<2.0.0-rc.1"},
+		{"1.2.3", DialectComposer, "=1.2.3"},
+		{"~1.2", DialectComposer, "(>=1.2 && 1.x)"},
+		{"~1.2.3", DialectComposer, "~1.2.3"},
+		{"1.0.*", DialectComposer, "1.0.*"},
+		{"<>1.2.3", DialectComposer, "!(=1.2.3)"},
+		{"v1.0.0 - 2.0", DialectComposer, "1.0.0 - 2.0"},
+		{"^1.2,!=1.3.0", DialectComposer, "(^1.2 && !(=1.3.0))"},
+		{">=1.0 <1.1 || >=1.2", DialectComposer, "((>=1.0 && <1.1) || >=1.2)"},
+	}
+	for _, test := range good {
+		t.Run(fmt.Sprintf("%s/%s", test.dialect, test.in), func(t *testing.T) {
+			c, err := ParseConstraintWithDialect(test.in, test.dialect)
+			require.NoError(t, err)
+			require.Equal(t, test.native, c.String())
+		})
+	}
+
+	bad := []struct {
+		in      string
+		dialect Dialect
+	}{
+		{"", DialectCargo + 10},
+		{"1 || 2", DialectCargo},
+		{">*", DialectNPM},
+		{">=1.2.*", DialectPEP440},
+		{"* - 2.0.0", DialectNPM},
+		{"1.2.3", DialectPEP440},
+		{"===1.0", DialectPEP440},
+		{"~=1", DialectPEP440},
+		{"1.0.0 |", DialectComposer},
+		{"1.0.0 - ", DialectNPM},
+		{">=a.b", DialectCargo},
+		{",", DialectComposer},
+		{">=1.0,,<2.0", DialectComposer},
+		{"9+c", DialectNPM},
+		{"~9+c", DialectComposer},
+		{"==1.2+c", DialectPEP440},
+		{"1.2+c - 2", DialectNPM},
+	}
+	for _, test := range bad {
+		t.Run(fmt.Sprintf("%s/%s", test.dialect, test.in), func(t *testing.T) {
+			_, err := ParseConstraintWithDialect(test.in, test.dialect)
+			require.Error(t, err)
+		})
+	}
+
+	// Partial versions behave like wildcards in npm
+	c, err := ParseConstraintWithDialect(">1.2", DialectNPM)
+	require.NoError(t, err)
+	require.False(t, c.Match(v("1.2.9")))
+	require.False(t, c.Match(v("1.3.0-alpha")))
+	require.True(t, c.Match(v("1.3.0")))
+	c, err = ParseConstraintWithDialect("1.2.3 - 2.x", DialectNPM)
+	require.NoError(t, err)
+	require.True(t, c.Match(v("2.9.0")))
+	require.False(t, c.Match(v("3.0.0")))
+	c, err = ParseConstraintWithDialect("^0", DialectNPM)
+	require.NoError(t, err)
+	require.True(t, c.Match(v("0.5.0")))
+	require.False(t, c.Match(v("1.0.0")))
+	c, err = ParseConstraintWithDialect("0.0", DialectCargo)
+	require.NoError(t, err)
+	require.True(t, c.Match(v("0.0.7")))
+	require.False(t, c.Match(v("0.1.0")))
+	c, err = ParseConstraintWithDialect("~=2.2", DialectPEP440)
+	require.NoError(t, err)
+	require.False(t, c.Match(v("2.1.0")))
+	require.True(t, c.Match(v("2.9.0")))
+	require.False(t, c.Match(v("3.0.0")))
+}
+
+func TestFormatConstraint(t *testing.T) {
+	tests := []struct {
+		in       string
+		npm      string
+		cargo    string
+		pep440   string
+		composer string
+	}{
+		{"", "*", "*", "", "*"},
+		{"=1.2", "1.2.0", "=1.2.0", "==1.2", "=1.2"},
+		{"^1.2.3", "^1.2.3", "^1.2.3", ">=1.2.3, <2.0.0", "^1.2.3"},
+		{"^0", ">=0.0.0 <0.0.1-0", ">=0.0.0, <0.0.1-0", ">=0, <0.0.1", ">=0, <0.0.1-0"},
+		{"^0.0", ">=0.0.0 <0.0.1-0", ">=0.0.0, <0.0.1-0", ">=0.0, <0.0.1", ">=0.0, <0.0.1-0"},
+		{"^1.2", ">=1.2.0 <2.0.0-0", ">=1.2.0, <2.0.0-0", ">=1.2, <2.0.0", ">=1.2, <2.0.0-0"},
+		{"~1.2", "~1.2", "~1.2", "~=1.2.0", "1.2.*"},
+		{"~1.2.3", "~1.2.3", "~1.2.3", "~=1.2.3", "~1.2.3"},
+		{"1.x", "1.*", "1.*", "==1.*", "1.*"},
+		{">=1.2.0 && <2", ">=1.2.0 <2.0.0", ">=1.2.0, <2.0.0", "", ">=1.2.0, <2"},
+		{">=1.2.0 && <2.0.0-0", ">=1.2.0 <2.0.0-0", ">=1.2.0, <2.0.0-0", ">=1.2.0, <2.0.0", ">=1.2.0, <2.0.0-0"},
+		{"1.0.0 - 2.0", "1.0.0 - 2.0", ">=1.0.0, <2.1.0-0", ">=1.0.0, <2.1.0", "1.0.0 - 2.0"},
+		{"!=1.2.3", "<1.2.3 || >1.2.3", "", "!=1.2.3", "!=1.2.3"},
+		{"!1.2.x", "<1.2.0 || >=1.3.0-0", "", "!=1.2.*", "<1.2.0 || >=1.3.0-0"},
+		{"(>=1.0 && <1.1) || >=1.2", ">=1.0.0 <1.1.0 || >=1.2.0", "", "", ">=1.0, <1.1 || >=1.2"},
+	}
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			c, err := ParseConstraint(test.in)
+			require.NoError(t, err)
+
+			res, err := FormatConstraint(c, DialectNative)
+			require.NoError(t, err)
+			require.Equal(t, c.String(), res)
+
+			for dialect, expected := range map[Dialect]string{
+				DialectNPM:      test.npm,
+				DialectCargo:    test.cargo,
+				DialectPEP440:   test.pep440,
+				DialectComposer: test.composer,
+			} {
+				res, err := FormatConstraint(c, dialect)
+				if expected == "" && test.in != "" {
+					require.Error(t, err, "%s: %s", dialect, res)
+					continue
+				}
+				require.NoError(t, err, dialect)
+				require.Equal(t, expected, res, dialect)
+
+				// The formatted constraint must match the same versions
+				parsed, err := ParseConstraintWithDialect(res, dialect)
+				require.NoError(t, err, "%s: %s", dialect, res)
+				for _, ver := range []string{"0.9.0", "1.0.0", "1.2.0", "1.2.3", "1.2.4-rc", "1.2.9", "1.3.0", "1.9.0", "2.0.0-rc", "2.0.0", "2.0.9", "2.1.0-rc", "2.1.0", "3.0.0"} {
+					require.Equal(t, c.Match(v(ver)), parsed.Match(v(ver)), "%s: %s on %s", dialect, res, ver)
+				}
+			}
+		})
+	}
+}
+
+func TestDialectRoundTrip(t *testing.T) {
+	inputs := map[Dialect][]string{
+		DialectNPM:      {"1.2.3 - 2.3", "^0", "^0.0", "^1.2", "~1.2.3", ">=1.2.0 <2.0.0-0", "1.2.x", ">1.2", "<=1.2", "1.x || >=2.5.0", "^1.2.x", ">1.x", "1.2.3 - 2.x"},
+		DialectCargo:    {"1.2", "0", "0.0", "^0.0.3", "~1.2", "=1.2", ">=1.2.0, <1.5", "1.*"},
+		DialectPEP440:   {"~=1.4.5", "~=2.2", ">=1.0, <2.0", "==1.2.*", "!=1.2.*", "<2.0.0-rc.1", ">=1.0, !=1.3.4, <2.0"},
+		DialectComposer: {"^1.2", "~1.2", "~1.2.3", "1.0.*", "<>1.2.3", "v1.0.0 - 2.0", ">=1.0 <1.1 || >=1.2"},
+	}
+	versions := []string{
+		"0.0.0", "0.0.3", "0.0.4", "0.1.0", "0.5.0", "0.9.0", "1.0.0", "1.2.0", "1.2.3", "1.2.4-rc", "1.2.9",
+		"1.3.0", "1.3.4", "1.4.5", "1.9.0", "2.0.0-rc", "2.0.0", "2.0.9", "2.2.0", "2.3.5", "2.4.0-rc", "2.4.0", "3.0.0",
+	}
+	for from, list := range inputs {
+		for _, in := range list {
+			c, err := ParseConstraintWithDialect(in, from)
+			require.NoError(t, err, "%s: %s", from, in)
+			for _, to := range []Dialect{DialectNPM, DialectCargo, DialectPEP440, DialectComposer} {
+				res, err := FormatConstraint(c, to)
+				if err != nil {
+					continue
+				}
+				parsed, err := ParseConstraintWithDialect(res, to)
+				require.NoError(t, err, "%s: %s -> %s: %s", from, in, to, res)
+				for _, ver := range versions {
+					require.Equal(t, c.Match(v(ver)), parsed.Match(v(ver)), "%s: %s -> %s: %s on %s", from, in, to, res, ver)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,158 @@
+//
+// Copyright 2018-2025 Cristian Maglie. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+
+package semver
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrMissingNumber is the kind of ParseError returned when a version number
+// is missing, like the minor number in "1."
+var ErrMissingNumber = errors.New("missing version number")
+
+// ErrLeadingZero is the kind of ParseError returned when a number is
+// prefixed with zero, like "01.2.3"
+var ErrLeadingZero = errors.New("number prefixed with zero")
+
+// ErrInvalidCharacter is the kind of ParseError returned when an unexpected
+// char is found, like the "a" in "1.2a"
+var ErrInvalidCharacter = errors.New("invalid character")
+
+// ErrEmptyIdentifier is the kind of ParseError returned when a pre-release
+// or build identifier is empty, like in "1.2.3-rc..1"
+var ErrEmptyIdentifier = errors.New("empty identifier")
+
+// VersionComponent is a part of a version
+type VersionComponent int
+
+const (
+	// ComponentMajor is the major number
+	ComponentMajor VersionComponent = iota
+	// ComponentMinor is the minor number
+	ComponentMinor
+	// ComponentPatch is the patch number
+	ComponentPatch
+	// ComponentPrerelease is the pre-release part
+	ComponentPrerelease
+	// ComponentBuild is the build metadata part
+	ComponentBuild
+	// ComponentRevision is the fourth number (see ParseOptions.AllowFourComponents)
+	ComponentRevision
+)
+
+func (c VersionComponent) String() string {
+	switch c {
+	case ComponentMajor:
+		return "major"
+	case ComponentMinor:
+		return "minor"
+	case ComponentPatch:
+		return "patch"
+	case ComponentPrerelease:
+		return "prerelease"
+	case ComponentBuild:
+		return "build"
+	case ComponentRevision:
+		return "revision"
+	}
+	return fmt.Sprintf("VersionComponent(%d)", int(c))
+}
+
+// ParseError is the error returned by Parse when the input is not a valid
+// version. The Kind of the error may be checked with errors.Is, for example
+// errors.Is(err, ErrLeadingZero).
+type ParseError struct {
+	// Input is the string being parsed
+	Input string
+	// Offset is the position in bytes of the error in Input
+	Offset int
+	// Component is the part of the version that failed to parse
+	Component VersionComponent
+	// Kind is one of ErrMissingNumber, ErrLeadingZero, ErrInvalidCharacter
+	// or ErrEmptyIdentifier
+	Kind error
+}
+
+func (e *ParseError) Error() string {
+	switch e.Kind {
+	case ErrMissingNumber:
+		return fmt.Sprintf("no %s version found", e.Component)
+	case ErrLeadingZero:
+		if e.Component == ComponentPrerelease {
+			return "numeric prerelease must not be prefixed with zero"
+		}
+		return fmt.Sprintf("%s version must not be prefixed with zero", e.Component)
+	case ErrEmptyIdentifier:
+		if e.Component == ComponentPrerelease {
+			return "empty prerelease not allowed"
+		}
+		return "empty build tag not allowed"
+	case ErrInvalidCharacter:
+		var c byte
+		if e.Offset < len(e.Input) {
+			c = e.Input[e.Offset]
+		}
+		switch e.Component {
+		case ComponentPrerelease:
+			return fmt.Sprintf("invalid prerelease separator: '%c'", c)
+		case ComponentBuild:
+			return fmt.Sprintf("invalid separator for builds: '%c'", c)
+		}
+		return fmt.Sprintf("invalid %s version separator '%c'", e.Component, c)
+	}
+	if e.Kind == nil {
+		return fmt.Sprintf("invalid version at offset %d", e.Offset)
+	}
+	return e.Kind.Error()
+}
+
+// Unwrap returns the Kind of the error
+func (e *ParseError) Unwrap() error {
+	return e.Kind
+}
+
+// ConstraintSyntaxError is the error returned by ParseConstraint when the
+// input is not a valid constraint.
+type ConstraintSyntaxError struct {
+	// Input is the string being parsed
+	Input string
+	// Offset is the position in bytes of the error in Input
+	Offset int
+	// Token is the offending token, it is empty if the input ended
+	// unexpectedly
+	Token string
+	// Expected is the list of tokens that were expected at Offset
+	Expected []string
+	// Err is the error returned by Parse if the offending token is a
+	// malformed version
+	Err error
+}
+
+func (e *ConstraintSyntaxError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("invalid version '%s' at offset %d: %s", e.Token, e.Offset, e.Err)
+	}
+	var expected []string
+	for _, token := range e.Expected {
+		expected = append(expected, "'"+token+"'")
+	}
+	res := fmt.Sprintf("unexpected '%s' at offset %d", e.Token, e.Offset)
+	if e.Token == "" {
+		res = fmt.Sprintf("unexpected end of constraint at offset %d", e.Offset)
+	}
+	if len(expected) > 0 {
+		res += ", expected " + strings.Join(expected, " or ")
+	}
+	return res
+}
+
+// Unwrap returns the error returned by Parse, if any
+func (e *ConstraintSyntaxError) Unwrap() error {
+	return e.Err
+}
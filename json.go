@@ -12,7 +12,11 @@ import (
 
 // MarshalJSON implements json.Marshaler
 func (v *Version) MarshalJSON() ([]byte, error) {
-	return json.Marshal(v.String())
+	res, err := v.encodedString()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(res)
 }
 
 // UnmarshalJSON implements json.Unmarshaler
@@ -33,6 +37,8 @@ func (v *Version) UnmarshalJSON(data []byte) error {
 	v.patch = parsed.patch
 	v.prerelease = parsed.prerelease
 	v.build = parsed.build
+	v.spelling = ""
+	v.revision = ""
 	return nil
 }
 
@@ -51,5 +57,7 @@ func (v *RelaxedVersion) UnmarshalJSON(data []byte) error {
 
 	v.customversion = parsed.customversion
 	v.version = parsed.version
+	v.coerced = parsed.coerced
+	v.ordering = parsed.ordering
 	return nil
 }
@@ -82,3 +82,26 @@ func BenchmarkJSONDecodingRelaxed(b *testing.B) {
 		_ = json.Unmarshal(data, &u)
 	}
 }
+
+func TestJSONParseVersionWithOptions(t *testing.T) {
+	v, err := ParseWithOptions("V01.02.3-rc+build", ParseOptions{AllowPrefix: true, AllowLeadingZeros: true, AllowFourComponents: true})
+	require.NoError(t, err)
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	require.Equal(t, `"1.2.3-rc+build"`, string(data))
+	var u Version
+	require.NoError(t, json.Unmarshal(data, &u))
+	require.Equal(t, "1.2.3-rc+build", u.String())
+	require.Zero(t, v.CompareTo(&u))
+
+	// The fourth number can't be encoded
+	v, err = ParseWithOptions("1.2.3.4", ParseOptions{AllowPrefix: true, AllowLeadingZeros: true, AllowFourComponents: true})
+	require.NoError(t, err)
+	_, err = json.Marshal(v)
+	require.Error(t, err)
+
+	// The decoder is strict
+	for _, in := range []string{`"v1.2.3"`, `"01.02.03"`, `"1.2.3.4"`, `"V0001.2"`} {
+		require.Error(t, json.Unmarshal([]byte(in), &u), in)
+	}
+}
@@ -0,0 +1,99 @@
+//
+// Copyright 2018-2025 Cristian Maglie. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+
+package semver
+
+import (
+	"fmt"
+	"slices"
+)
+
+// minimalVersionSelection implements the Minimal Version Selection algorithm
+// as described in https://research.swtch.com/vgo-mvs: for each package the
+// minimum release satisfying the highest of the lower bounds required by all
+// the reachable releases is selected.
+func (r *resolution[R, D]) minimalVersionSelection(reqs []*Requirement[R, D]) (Releases[R, D], error) {
+	if r.hasRoot {
+		r.solution[r.root.GetName()] = r.root
+	}
+	visited := map[string]bool{}
+	queue := slices.Clone(reqs)
+	for len(queue) > 0 {
+		req := queue[0]
+		queue = queue[1:]
+		if err := r.step(len(req.Path)); err != nil {
+			return nil, r.aborted(err)
+		}
+
+		dep := req.Dependency
+		depName := dep.GetName()
+		if set, ok := constraintToVersionSet(dep.GetConstraint()); !ok || len(set) != 1 {
+			return nil, fmt.Errorf("%w: %s", ErrNotLowerBound, req)
+		}
+		if r.hasRoot && depName == r.root.GetName() {
+			// The root release is always selected, it will be checked later
+			continue
+		}
+
+		available, err := r.releasesFor(depName)
+		if err != nil {
+			return nil, r.aborted(err)
+		}
+		if len(available) == 0 {
+			debug("%s does not exist", depName)
+			r.missing[depName] = true
+			r.addMissingRoot(req)
+			continue
+		}
+		releases := r.selectable(depName, available).FilterByContext(dep.GetConstraint(), r.matchCtx)
+		if len(releases) == 0 {
+			r.addConflict(depName, req.dependencyKey(), nil, req)
+			continue
+		}
+		release := slices.MinFunc(releases, func(a, b R) int {
+			return a.GetVersion().CompareTo(b.GetVersion())
+		})
+		debug("%s requires at least %s", req, releaseString(release))
+
+		// Keep the highest of the minimum releases
+		if selected, has := r.solution[depName]; !has || selected.GetVersion().LessThan(release.GetVersion()) {
+			r.solution[depName] = release
+			r.selectedBy[depName] = req
+			r.updateBest()
+		}
+		if key := releaseString(release); !visited[key] {
+			visited[key] = true
+			queue = append(queue, requirementsOf(release, req.Path)...)
+		}
+	}
+	if len(r.conflicts) > 0 || len(r.missing) > 0 {
+		return nil, r.report()
+	}
+
+	// Check that the selected releases satisfy all the requirements, the
+	// upper bounds of the constraints may be exceeded.
+	check := slices.Clone(reqs)
+	res := Releases[R, D]{}
+	for depName, release := range r.solution {
+		res = append(res, release)
+		if r.hasRoot && depName == r.root.GetName() {
+			continue
+		}
+		check = append(check, requirementsOf(release, r.selectedBy[depName].Path)...)
+	}
+	for _, req := range check {
+		dep := req.Dependency
+		depName := dep.GetName()
+		if r.matchCtx.Match(dep.GetConstraint(), r.solution[depName].GetVersion()) {
+			continue
+		}
+		r.addConflict(depName, req.dependencyKey(), r.selectedBy[depName], req)
+	}
+	if len(r.conflicts) > 0 {
+		return nil, r.report()
+	}
+	return res, nil
+}
@@ -0,0 +1,159 @@
+//
+// Copyright 2018-2025 Cristian Maglie. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+
+package semver
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ParseOptions are the options of ParseWithOptions
+type ParseOptions struct {
+	// AllowPrefix allows a leading "v" or "V", like in the git tag "v1.2.3".
+	// The prefix is kept by String() but it is ignored when comparing versions.
+	AllowPrefix bool
+
+	// TrimSpace removes the leading and trailing white spaces
+	TrimSpace bool
+
+	// AllowLeadingZeros allows the major, minor and patch numbers to be
+	// prefixed with zeros, like in "1.02.003". The zeros are ignored when
+	// comparing versions.
+	AllowLeadingZeros bool
+
+	// AllowFourComponents allows versions with four numbers, like "1.2.3.4".
+	// The fourth number is compared after the patch number and before the
+	// pre-release, a missing fourth number is equivalent to 0.
+	AllowFourComponents bool
+
+	// Strict rejects the truncated versions, like "1" or "1.2", that are
+	// otherwise accepted.
+	Strict bool
+}
+
+// ParseWithOptions parse a version string with the given options. The
+// version is stored in its canonical form, that is used for comparison,
+// while String() returns the original spelling. The JSON, YAML, SQL and
+// binary encoders write the canonical form, that is accepted by Parse, and
+// the JSON, YAML and SQL encoders fail if the version has a fourth number.
+//
+// If the string is not a valid version a *ParseError is returned, the Offset
+// of the error refers to the original string.
+func ParseWithOptions(in string, opts ParseOptions) (*Version, error) {
+	// canonical is the version to be parsed and offsets maps each byte of
+	// canonical to the position of the same byte in the original string
+	canonical := in
+	offsets := make([]int, len(in)+1)
+	for i := range offsets {
+		offsets[i] = i
+	}
+	cut := func(from, to int) {
+		canonical = canonical[:from] + canonical[to:]
+		offsets = append(offsets[:from], offsets[to:]...)
+	}
+
+	if opts.TrimSpace {
+		trimmed := strings.TrimRightFunc(canonical, unicode.IsSpace)
+		cut(len(trimmed), len(canonical))
+		cut(0, len(trimmed)-len(strings.TrimLeftFunc(trimmed, unicode.IsSpace)))
+	}
+	spelling := canonical
+	if opts.AllowPrefix && (strings.HasPrefix(canonical, "v") || strings.HasPrefix(canonical, "V")) {
+		cut(0, 1)
+	}
+
+	// Split the numbers from the pre-release and build parts
+	end := strings.IndexAny(canonical, "-+")
+	if end == -1 {
+		end = len(canonical)
+	}
+	numbers := strings.Split(canonical[:end], ".")
+	if opts.AllowLeadingZeros {
+		pos := 0
+		for i, n := range numbers {
+			if i == 3 {
+				break
+			}
+			if zeros := len(n) - len(strings.TrimLeft(n, "0")); zeros > 0 && isNumericIdentifier(n) {
+				if zeros == len(n) {
+					zeros--
+				}
+				cut(pos, pos+zeros)
+				numbers[i] = n[zeros:]
+			}
+			pos += len(numbers[i]) + 1
+		}
+	}
+	revision := ""
+	if opts.AllowFourComponents && len(numbers) == 4 {
+		pos := len(numbers[0]) + len(numbers[1]) + len(numbers[2]) + 2
+		revision = numbers[3]
+		if revision == "" {
+			return nil, &ParseError{Input: in, Offset: offsets[pos+1], Component: ComponentRevision, Kind: ErrMissingNumber}
+		}
+		if i := strings.IndexFunc(revision, func(c rune) bool { return c < '0' || c > '9' }); i != -1 {
+			return nil, &ParseError{Input: in, Offset: offsets[pos+1+i], Component: ComponentRevision, Kind: ErrInvalidCharacter}
+		}
+		if zeros := len(revision) - len(strings.TrimLeft(revision, "0")); zeros > 0 {
+			if zeros == len(revision) {
+				zeros--
+			}
+			if zeros > 0 && !opts.AllowLeadingZeros {
+				return nil, &ParseError{Input: in, Offset: offsets[pos+1], Component: ComponentRevision, Kind: ErrLeadingZero}
+			}
+			revision = revision[zeros:]
+		}
+		if revision == "0" {
+			revision = ""
+		}
+		cut(pos, pos+len(numbers[3])+1)
+	}
+
+	res := &Version{
+		raw:   canonical,
+		bytes: []byte(canonical),
+	}
+	if err := parse(res); err != nil {
+		var parseErr *ParseError
+		if errors.As(err, &parseErr) {
+			parseErr.Input = in
+			parseErr.Offset = offsets[parseErr.Offset]
+		}
+		return nil, err
+	}
+	if opts.Strict {
+		if components := versionComponents(res); res.major == 0 || components < 3 {
+			component := ComponentPatch
+			if res.major == 0 {
+				component = ComponentMajor
+			} else if components == 1 {
+				component = ComponentMinor
+			}
+			return nil, &ParseError{Input: in, Offset: offsets[res.patch], Component: component, Kind: ErrMissingNumber}
+		}
+	}
+	res.revision = revision
+	if spelling != canonical {
+		res.spelling = spelling
+	}
+	return res, nil
+}
+
+// encodedString returns the canonical form of the version, used by the JSON,
+// YAML and SQL encoders so the result can be decoded with Parse. An error is
+// returned if the version has a fourth number, that can't be represented.
+func (v *Version) encodedString() (string, error) {
+	if v == nil {
+		return "", nil
+	}
+	if v.revision != "" {
+		return "", fmt.Errorf("version %s has four numbers and can't be encoded as semver", v)
+	}
+	return v.raw, nil
+}
@@ -0,0 +1,164 @@
+//
+// Copyright 2018-2025 Cristian Maglie. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+
+package semver
+
+import (
+	"strings"
+)
+
+// CustomOrdering is the ordering used to compare the RelaxedVersion that do
+// not comply with semver
+type CustomOrdering int
+
+const (
+	// OrderingDefault selects the ordering set in DefaultCustomOrdering
+	OrderingDefault CustomOrdering = iota
+	// OrderingAlphabetical compares the versions as strings, so "build-10"
+	// is less than "build-9"
+	OrderingAlphabetical
+	// OrderingNatural compares the groups of digits by their numeric value
+	// and the rest as strings, so "build-9" is less than "build-10". Numbers
+	// with the same value are ordered by the count of leading zeros.
+	OrderingNatural
+	// OrderingDebian compares the versions like "dpkg --compare-versions":
+	// the optional epoch (before the first ":") is compared first, then the
+	// upstream version and the revision (after the last "-"). In each part the
+	// groups of digits are compared by value, letters sort before the other
+	// characters and "~" sorts before anything, even the end of the part.
+	// Different strings may be equal, like "1.01" and "1.1".
+	OrderingDebian
+)
+
+func (o CustomOrdering) String() string {
+	switch o {
+	case OrderingDefault:
+		return "default"
+	case OrderingAlphabetical:
+		return "alphabetical"
+	case OrderingNatural:
+		return "natural"
+	case OrderingDebian:
+		return "debian"
+	}
+	return "unknown"
+}
+
+// DefaultCustomOrdering is the ordering used by the RelaxedVersion parsed
+// with OrderingDefault, including the ones parsed with ParseRelaxed and by
+// the JSON, YAML, SQL and binary decoders (when the binary data does not
+// specify an ordering). Changing it does not affect the versions already
+// parsed, while the zero value RelaxedVersion always follows it.
+var DefaultCustomOrdering = OrderingAlphabetical
+
+// resolve returns the ordering to use in place of OrderingDefault
+func (o CustomOrdering) resolve() CustomOrdering {
+	if o == OrderingDefault {
+		return DefaultCustomOrdering
+	}
+	return o
+}
+
+// customKey returns a string that, when compared alphabetically with the
+// keys of the other custom versions, respects the given ordering.
+func customKey(in []byte, ordering CustomOrdering) string {
+	switch ordering.resolve() {
+	case OrderingNatural:
+		return naturalKey(in)
+	case OrderingDebian:
+		return debianKey(string(in))
+	}
+	return string(in)
+}
+
+// encodeLength encodes a length in a string that respects the numeric
+// ordering and can be read back without a separator: the digits from "0" to
+// "8" for the lengths up to 8, preceded by a "9" for each additional 9.
+// For example: 3 -> "3", 9 -> "90", 20 -> "992".
+func encodeLength(n int) string {
+	return strings.Repeat("9", n/9) + string(rune('0'+n%9))
+}
+
+// naturalKey encodes each group of digits as the length of the number
+// without leading zeros, followed by the number and by the count of the
+// leading zeros. The other characters are kept as-is. The encoded number
+// starts with a digit so it compares with the other characters as the
+// original number.
+func naturalKey(in []byte) string {
+	var res strings.Builder
+	for i := 0; i < len(in); {
+		if !isNumeric(in[i]) {
+			res.WriteByte(in[i])
+			i++
+			continue
+		}
+		start := i
+		for i < len(in) && isNumeric(in[i]) {
+			i++
+		}
+		number := string(in[start:i])
+		digits := strings.TrimLeft(number, "0")
+		res.WriteString(encodeLength(len(digits)))
+		res.WriteString(digits)
+		res.WriteString(encodeLength(len(number) - len(digits)))
+	}
+	return res.String()
+}
+
+// debianKey encodes the epoch, the upstream version and the revision of a
+// Debian version string
+func debianKey(in string) string {
+	epoch := ""
+	if i := strings.IndexByte(in, ':'); i != -1 && isNumericIdentifier(in[:i]) {
+		epoch, in = in[:i], in[i+1:]
+	}
+	revision := ""
+	if i := strings.LastIndexByte(in, '-'); i != -1 {
+		in, revision = in[:i], in[i+1:]
+	}
+	return encodeDebianNumber(epoch) + encodeDebianPart(in) + encodeDebianPart(revision)
+}
+
+// encodeDebianNumber encodes a group of digits ignoring the leading zeros,
+// an empty group is equivalent to 0.
+func encodeDebianNumber(number string) string {
+	digits := strings.TrimLeft(number, "0")
+	return encodeLength(len(digits)) + digits
+}
+
+// encodeDebianPart encodes a Debian upstream version or revision as a
+// sequence of non-digit strings followed by a number. Each character is
+// prefixed by its class to obtain the dpkg ordering:
+//
+//	"0" for "~", "1" for the end of the part, "2" for the end of the
+//	non-digit string, "3" for the letters and "4" for the other characters.
+func encodeDebianPart(in string) string {
+	var res strings.Builder
+	for first := true; first || in != ""; first = false {
+		i := 0
+		for ; i < len(in) && !isNumeric(in[i]); i++ {
+			switch c := in[i]; {
+			case c == '~':
+				res.WriteString("0")
+			case (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z'):
+				res.WriteString("3")
+				res.WriteByte(c)
+			default:
+				res.WriteString("4")
+				res.WriteByte(c)
+			}
+		}
+		res.WriteString("2")
+		j := i
+		for j < len(in) && isNumeric(in[j]) {
+			j++
+		}
+		res.WriteString(encodeDebianNumber(in[i:j]))
+		in = in[j:]
+	}
+	res.WriteString("1")
+	return res.String()
+}
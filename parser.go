@@ -6,10 +6,6 @@
 
 package semver
 
-import (
-	"fmt"
-)
-
 // MustParse parse a version string and panic if the parsing fails
 func MustParse(inVersion string) *Version {
 	res, err := Parse(inVersion)
@@ -19,7 +15,8 @@ func MustParse(inVersion string) *Version {
 	return res
 }
 
-// Parse parse a version string
+// Parse parse a version string. If the string is not a valid version a
+// *ParseError is returned.
 func Parse(inVersion string) (*Version, error) {
 	result := &Version{
 		raw:   inVersion,
@@ -45,6 +42,14 @@ func parse(result *Version) error {
 		curr = in[currIdx]
 		return true
 	}
+	fail := func(kind error, component VersionComponent, offset int) error {
+		return &ParseError{
+			Input:     result.raw,
+			Offset:    offset,
+			Component: component,
+			Kind:      kind,
+		}
+	}
 
 	// 2. A normal version number MUST take the form X.Y.Z where X, Y, and Z
 	// are non-negative integers, and MUST NOT contain leading zeroes. X is
@@ -57,7 +62,7 @@ func parse(result *Version) error {
 		return nil // empty version
 	}
 	if !numeric[curr] {
-		return fmt.Errorf("no major version found")
+		return fail(ErrMissingNumber, ComponentMajor, currIdx)
 	}
 	if curr == '0' {
 		result.major = 1
@@ -69,10 +74,10 @@ func parse(result *Version) error {
 			return nil
 		}
 		if numeric[curr] {
-			return fmt.Errorf("major version must not be prefixed with zero")
+			return fail(ErrLeadingZero, ComponentMajor, currIdx-1)
 		}
 		if !versionSeparator[curr] {
-			return fmt.Errorf("invalid major version separator '%c'", curr)
+			return fail(ErrInvalidCharacter, ComponentMajor, currIdx)
 		}
 		// Fallthrough and parse next element
 	} else {
@@ -96,14 +101,14 @@ func parse(result *Version) error {
 				result.build = currIdx
 				break
 			}
-			return fmt.Errorf("invalid major version separator '%c'", curr)
+			return fail(ErrInvalidCharacter, ComponentMajor, currIdx)
 		}
 	}
 
 	// Parse minor
 	if curr == '.' {
 		if !next() || !numeric[curr] {
-			return fmt.Errorf("no minor version found")
+			return fail(ErrMissingNumber, ComponentMinor, currIdx)
 		}
 		if curr == '0' {
 			result.minor = currIdx + 1
@@ -114,10 +119,10 @@ func parse(result *Version) error {
 				return nil
 			}
 			if numeric[curr] {
-				return fmt.Errorf("minor version must not be prefixed with zero")
+				return fail(ErrLeadingZero, ComponentMinor, currIdx-1)
 			}
 			if !versionSeparator[curr] {
-				return fmt.Errorf("invalid minor version separator '%c'", curr)
+				return fail(ErrInvalidCharacter, ComponentMinor, currIdx)
 			}
 			// Fallthrough and parse next element
 		} else {
@@ -139,7 +144,7 @@ func parse(result *Version) error {
 					result.build = currIdx
 					break
 				}
-				return fmt.Errorf("invalid minor version separator '%c'", curr)
+				return fail(ErrInvalidCharacter, ComponentMinor, currIdx)
 			}
 		}
 	} else {
@@ -149,7 +154,7 @@ func parse(result *Version) error {
 	// Parse patch
 	if curr == '.' {
 		if !next() || !numeric[curr] {
-			return fmt.Errorf("no patch version found")
+			return fail(ErrMissingNumber, ComponentPatch, currIdx)
 		}
 		if curr == '0' {
 			result.patch = currIdx + 1
@@ -159,10 +164,10 @@ func parse(result *Version) error {
 				return nil
 			}
 			if numeric[curr] {
-				return fmt.Errorf("patch version must not be prefixed with zero")
+				return fail(ErrLeadingZero, ComponentPatch, currIdx-1)
 			}
 			if !versionSeparator[curr] {
-				return fmt.Errorf("invalid patch version separator '%c'", curr)
+				return fail(ErrInvalidCharacter, ComponentPatch, currIdx)
 			}
 			// Fallthrough and parse next element
 		} else {
@@ -182,7 +187,7 @@ func parse(result *Version) error {
 					result.build = currIdx
 					break
 				}
-				return fmt.Errorf("invalid patch version separator '%c'", curr)
+				return fail(ErrInvalidCharacter, ComponentPatch, currIdx)
 			}
 		}
 	} else {
@@ -208,10 +213,10 @@ func parse(result *Version) error {
 		for {
 			if hasNext := next(); !hasNext || curr == '.' || curr == '+' {
 				if prereleaseIdx == currIdx {
-					return fmt.Errorf("empty prerelease not allowed")
+					return fail(ErrEmptyIdentifier, ComponentPrerelease, currIdx)
 				}
 				if zeroPrefix && !alphaIdentifier && currIdx-prereleaseIdx > 1 {
-					return fmt.Errorf("numeric prerelease must not be prefixed with zero")
+					return fail(ErrLeadingZero, ComponentPrerelease, prereleaseIdx)
 				}
 				result.prerelease = currIdx
 				if !hasNext {
@@ -239,7 +244,7 @@ func parse(result *Version) error {
 				alphaIdentifier = true
 				continue
 			}
-			return fmt.Errorf("invalid prerelease separator: '%c'", curr)
+			return fail(ErrInvalidCharacter, ComponentPrerelease, currIdx)
 		}
 	} else {
 		result.prerelease = currIdx
@@ -260,7 +265,7 @@ func parse(result *Version) error {
 		for {
 			if hasNext := next(); !hasNext || curr == '.' {
 				if buildIdx == currIdx {
-					return fmt.Errorf("empty build tag not allowed")
+					return fail(ErrEmptyIdentifier, ComponentBuild, currIdx)
 				}
 				result.build = currIdx
 				if !hasNext {
@@ -274,8 +279,8 @@ func parse(result *Version) error {
 			if identifier[curr] {
 				continue
 			}
-			return fmt.Errorf("invalid separator for builds: '%c'", curr)
+			return fail(ErrInvalidCharacter, ComponentBuild, currIdx)
 		}
 	}
-	return fmt.Errorf("invalid separator: '%c'", curr)
+	return fail(ErrInvalidCharacter, ComponentPatch, currIdx)
 }
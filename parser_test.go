@@ -7,6 +7,8 @@
 package semver
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
 	"testing"
 
@@ -228,6 +230,143 @@ func TestParser(t *testing.T) {
 	})
 }
 
+func TestParseError(t *testing.T) {
+	tests := []struct {
+		in        string
+		offset    int
+		component VersionComponent
+		kind      error
+		msg       string
+	}{
+		{"a.2.3", 0, ComponentMajor, ErrMissingNumber, "no major version found"},
+		{"01.2.3", 0, ComponentMajor, ErrLeadingZero, "major version must not be prefixed with zero"},
+		{"1a.2.3", 1, ComponentMajor, ErrInvalidCharacter, "invalid major version separator 'a'"},
+		{"1.", 2, ComponentMinor, ErrMissingNumber, "no minor version found"},
+		{"1.02.3", 2, ComponentMinor, ErrLeadingZero, "minor version must not be prefixed with zero"},
+		{"1.2a", 3, ComponentMinor, ErrInvalidCharacter, "invalid minor version separator 'a'"},
+		{"1.2.x", 4, ComponentPatch, ErrMissingNumber, "no patch version found"},
+		{"1.2.03", 4, ComponentPatch, ErrLeadingZero, "patch version must not be prefixed with zero"},
+		{"1.2.3.4", 5, ComponentPatch, ErrInvalidCharacter, "invalid patch version separator '.'"},
+		{"1.2.0.4", 5, ComponentPatch, ErrInvalidCharacter, "invalid patch version separator '.'"},
+		{"1.2.3-rc..1", 9, ComponentPrerelease, ErrEmptyIdentifier, "empty prerelease not allowed"},
+		{"1.2.3-rc.01", 9, ComponentPrerelease, ErrLeadingZero, "numeric prerelease must not be prefixed with zero"},
+		{"1.2.3-rc_1", 8, ComponentPrerelease, ErrInvalidCharacter, "invalid prerelease separator: '_'"},
+		{"1.2.3+", 6, ComponentBuild, ErrEmptyIdentifier, "empty build tag not allowed"},
+		{"1.2.3+a/b", 7, ComponentBuild, ErrInvalidCharacter, "invalid separator for builds: '/'"},
+	}
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			_, err := Parse(test.in)
+			var parseErr *ParseError
+			require.ErrorAs(t, err, &parseErr)
+			require.ErrorIs(t, err, test.kind)
+			require.Equal(t, test.in, parseErr.Input)
+			require.Equal(t, test.offset, parseErr.Offset)
+			require.Equal(t, test.component, parseErr.Component)
+			require.EqualError(t, err, test.msg)
+		})
+	}
+
+	// Errors in the versions of a constraint are wrapped
+	_, err := ParseConstraint(">=1.02.3")
+	require.ErrorIs(t, err, ErrLeadingZero)
+	var parseErr *ParseError
+	require.ErrorAs(t, err, &parseErr)
+	require.Equal(t, ComponentMinor, parseErr.Component)
+	require.Equal(t, "minor", parseErr.Component.String())
+
+	// The zero value and custom kinds do not panic
+	require.EqualError(t, &ParseError{}, "invalid version at offset 0")
+	require.EqualError(t, &ParseError{Offset: 3, Kind: errors.New("custom")}, "custom")
+}
+
+func TestParseWithOptions(t *testing.T) {
+	all := ParseOptions{
+		AllowPrefix:         true,
+		TrimSpace:           true,
+		AllowLeadingZeros:   true,
+		AllowFourComponents: true,
+	}
+	valid := func(in string, opts ParseOptions, expected, equivalent string) *Version {
+		v, err := ParseWithOptions(in, opts)
+		require.NoError(t, err, "parsing '%s'", in)
+		require.Equal(t, expected, v.String())
+		if equivalent != "" {
+			require.Zero(t, v.CompareTo(MustParse(equivalent)), "comparing %s with %s", in, equivalent)
+		}
+		return v
+	}
+	valid("1.2.3", ParseOptions{}, "1.2.3", "1.2.3")
+	valid("v1.2.3", all, "v1.2.3", "1.2.3")
+	valid("V1.2-rc+b", all, "V1.2-rc+b", "1.2.0-rc")
+	valid("  v1.2.3\n", all, "v1.2.3", "1.2.3")
+	valid(" 1.2.3 ", ParseOptions{TrimSpace: true}, "1.2.3", "1.2.3")
+	valid("01.002.0003", all, "01.002.0003", "1.2.3")
+	valid("00.00.00-rc.1", all, "00.00.00-rc.1", "0.0.0-rc.1")
+	valid("1.2.3.0", all, "1.2.3.0", "1.2.3")
+	valid("1.2.3.4", all, "1.2.3.4", "")
+	valid("1.2", ParseOptions{Strict: false}, "1.2", "1.2.0")
+	valid("1.2.3", ParseOptions{Strict: true}, "1.2.3", "1.2.3")
+	valid("v1.2.3.4", ParseOptions{Strict: true, AllowPrefix: true, AllowFourComponents: true}, "v1.2.3.4", "")
+
+	// The prefix is kept by String() but it is ignored in comparison
+	v := valid("v1.2.3", all, "v1.2.3", "1.2.3")
+	require.True(t, v.Equal(MustParse("1.2.3")))
+	require.Equal(t, MustParse("1.2.3").SortableString(), v.SortableString())
+	require.Equal(t, "1.2.3", string(v.NormalizedString()))
+	v.Normalize()
+	require.Equal(t, "1.2.3", v.String())
+
+	// The fourth number is compared after the patch and before the pre-release
+	list := []string{"1.2.3-rc", "1.2.3", "1.2.3.0", "1.2.3.1-rc", "1.2.3.1", "1.2.3.2", "1.2.3.10", "1.2.4-0", "1.2.4"}
+	for i := range list {
+		for j := range list {
+			a, err := ParseWithOptions(list[i], all)
+			require.NoError(t, err)
+			b, err := ParseWithOptions(list[j], all)
+			require.NoError(t, err)
+			expected := cmp.Compare(i, j)
+			if list[i] == "1.2.3" && list[j] == "1.2.3.0" || list[i] == "1.2.3.0" && list[j] == "1.2.3" {
+				expected = 0
+			}
+			require.Equal(t, expected, a.CompareTo(b), "comparing %s with %s", a, b)
+			require.Equal(t, expected, cmp.Compare(a.SortableString(), b.SortableString()), "comparing sortable %s with %s", a, b)
+		}
+	}
+	v = valid("1.2.3.4-rc+b", all, "1.2.3.4-rc+b", "")
+	require.Equal(t, "1.2.3.4-rc+b", string(v.NormalizedString()))
+	require.NotEqual(t, MustParse("1.2.3-rc+b").NormalizedString(), v.NormalizedString())
+	v = valid("1.2.4", all, "1.2.4", "1.2.4")
+	require.Equal(t, "1.2.4", string(v.NormalizedString()))
+	v = valid("1.2.0.4", all, "1.2.0.4", "")
+	v.Normalize()
+	require.Equal(t, "1.2.0.4", v.String())
+
+	invalid := func(in string, opts ParseOptions, kind error, component VersionComponent, offset int) {
+		v, err := ParseWithOptions(in, opts)
+		require.Nil(t, v)
+		var parseErr *ParseError
+		require.ErrorAs(t, err, &parseErr, "parsing '%s'", in)
+		require.ErrorIs(t, err, kind, "parsing '%s'", in)
+		require.Equal(t, in, parseErr.Input)
+		require.Equal(t, component, parseErr.Component, "parsing '%s'", in)
+		require.Equal(t, offset, parseErr.Offset, "parsing '%s'", in)
+	}
+	invalid("v1.2.3", ParseOptions{}, ErrMissingNumber, ComponentMajor, 0)
+	invalid(" 1.2.3", ParseOptions{}, ErrMissingNumber, ComponentMajor, 0)
+	invalid("01.2.3", ParseOptions{}, ErrLeadingZero, ComponentMajor, 0)
+	invalid("1.2.3.4", ParseOptions{}, ErrInvalidCharacter, ComponentPatch, 5)
+	invalid("  v1.2.3a", all, ErrInvalidCharacter, ComponentPatch, 8)
+	invalid("v01.002.3-rc.01", all, ErrLeadingZero, ComponentPrerelease, 13)
+	invalid("1.2.3.", all, ErrMissingNumber, ComponentRevision, 6)
+	invalid("v1.2.3.4a", all, ErrInvalidCharacter, ComponentRevision, 8)
+	invalid("1.2.3.04", ParseOptions{AllowFourComponents: true}, ErrLeadingZero, ComponentRevision, 6)
+	invalid("1.2.3.4.5", all, ErrInvalidCharacter, ComponentPatch, 5)
+	invalid("", ParseOptions{Strict: true}, ErrMissingNumber, ComponentMajor, 0)
+	invalid("1", ParseOptions{Strict: true}, ErrMissingNumber, ComponentMinor, 1)
+	invalid("v1.2-rc", ParseOptions{Strict: true, AllowPrefix: true}, ErrMissingNumber, ComponentPatch, 4)
+}
+
 func TestNilVersionStringOutput(t *testing.T) {
 	var nilVersion *Version
 	require.Equal(t, "", nilVersion.String())
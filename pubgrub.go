@@ -0,0 +1,769 @@
+//
+// Copyright 2018-2025 Cristian Maglie. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+
+package semver
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// This file implements the PubGrub version solving algorithm as described in:
+// https://github.com/dart-lang/pub/blob/master/doc/solver.md
+
+// term is a statement about a package that may be true or false for a given
+// selection of releases: a positive term is satisfied if a release of the
+// package is selected and its version is in the set, a negative term is
+// satisfied if no release of the package is selected or if the selected
+// version is not in the set.
+type term struct {
+	pkg      string
+	set      versionSet
+	positive bool
+}
+
+func (t term) negate() term {
+	return term{pkg: t.pkg, set: t.set, positive: !t.positive}
+}
+
+// intersect returns a term that is satisfied only if both t and u are
+// satisfied, t and u must refer to the same package.
+func (t term) intersect(u term) term {
+	switch {
+	case t.positive && u.positive:
+		return term{pkg: t.pkg, set: t.set.intersect(u.set), positive: true}
+	case t.positive:
+		return term{pkg: t.pkg, set: t.set.difference(u.set), positive: true}
+	case u.positive:
+		return term{pkg: t.pkg, set: u.set.difference(t.set), positive: true}
+	default:
+		return term{pkg: t.pkg, set: t.set.union(u.set), positive: false}
+	}
+}
+
+// difference returns a term that is satisfied if t is satisfied and u is not
+func (t term) difference(u term) term {
+	return t.intersect(u.negate())
+}
+
+// isEmpty returns true if the term can never be satisfied
+func (t term) isEmpty() bool {
+	return t.positive && t.set.isEmpty()
+}
+
+func (t term) equal(u term) bool {
+	return t.positive == u.positive && t.set.equal(u.set)
+}
+
+// satisfies returns true if t being satisfied implies that u is satisfied
+func (t term) satisfies(u term) bool {
+	return t.intersect(u).equal(t)
+}
+
+// contradicts returns true if t and u can not be satisfied together
+func (t term) contradicts(u term) bool {
+	return t.intersect(u).isEmpty()
+}
+
+func (t term) String() string {
+	return packageSetString(t.pkg, t.set)
+}
+
+func packageSetString(pkg string, set versionSet) string {
+	if set.isFull() {
+		return pkg
+	}
+	if len(set) == 1 && set[0].lower != nil && set[0].upper != nil && set[0].upper.Equal(successor(set[0].lower)) {
+		return pkg + "@" + set[0].lower.String()
+	}
+	if constraint := set.String(); constraint[0] == '(' || constraint[0] == '!' {
+		return pkg + " " + constraint
+	} else {
+		return pkg + constraint
+	}
+}
+
+type incompatibilityCause int
+
+const (
+	causeRoot incompatibilityCause = iota
+	causeDependency
+	causeNoVersions
+	causeMissing
+	causeDerived
+)
+
+// incompatibility is a set of terms that must not be all satisfied together
+type incompatibility struct {
+	terms  []term
+	cause  incompatibilityCause
+	causes [2]*incompatibility
+	// description is the human readable description of external
+	// incompatibilities
+	description string
+}
+
+func newIncompatibility(terms []term, cause incompatibilityCause, description string) *incompatibility {
+	// Merge the terms referring to the same package
+	var merged []term
+	index := map[string]int{}
+	for _, t := range terms {
+		if i, ok := index[t.pkg]; ok {
+			merged[i] = merged[i].intersect(t)
+			continue
+		}
+		index[t.pkg] = len(merged)
+		merged = append(merged, t)
+	}
+	return &incompatibility{terms: merged, cause: cause, description: description}
+}
+
+func (inc *incompatibility) String() string {
+	if inc.cause != causeDerived {
+		return inc.description
+	}
+	switch len(inc.terms) {
+	case 0:
+		return "version solving failed"
+	case 1:
+		if t := inc.terms[0]; t.positive {
+			return t.String() + " is forbidden"
+		} else {
+			return t.String() + " is required"
+		}
+	case 2:
+		t1, t2 := inc.terms[0], inc.terms[1]
+		if t1.positive && !t2.positive {
+			return t1.String() + " requires " + t2.String()
+		}
+		if !t1.positive && t2.positive {
+			return t2.String() + " requires " + t1.String()
+		}
+		if t1.positive && t2.positive {
+			return t1.String() + " is incompatible with " + t2.String()
+		}
+	}
+	var terms []string
+	for _, t := range inc.terms {
+		if t.positive {
+			terms = append(terms, t.String())
+		} else {
+			terms = append(terms, "not "+t.String())
+		}
+	}
+	return "one of " + strings.Join(terms, ", ") + " must be false"
+}
+
+// explain returns a human readable derivation tree of the incompatibility
+func (inc *incompatibility) explain() string {
+	var lines []string
+	ids := map[*incompatibility]int{}
+	var visit func(inc *incompatibility) string
+	visit = func(inc *incompatibility) string {
+		if inc.cause != causeDerived {
+			return inc.String()
+		}
+		if id, ok := ids[inc]; ok {
+			return fmt.Sprintf("%s (%d)", inc, id)
+		}
+		c1 := visit(inc.causes[0])
+		c2 := visit(inc.causes[1])
+		id := len(lines) + 1
+		ids[inc] = id
+		lines = append(lines, fmt.Sprintf("%d. Because %s and %s, %s.", id, c1, c2, inc))
+		return fmt.Sprintf("%s (%d)", inc, id)
+	}
+	visit(inc)
+	return strings.Join(lines, "\n")
+}
+
+// external returns the external incompatibilities that contributed to derive
+// the incompatibility, in the order they appear in the derivation.
+func (inc *incompatibility) external() []*incompatibility {
+	var res []*incompatibility
+	visited := map[*incompatibility]bool{}
+	var visit func(inc *incompatibility)
+	visit = func(inc *incompatibility) {
+		if visited[inc] {
+			return
+		}
+		visited[inc] = true
+		if inc.cause == causeDerived {
+			visit(inc.causes[0])
+			visit(inc.causes[1])
+		} else {
+			res = append(res, inc)
+		}
+	}
+	visit(inc)
+	return res
+}
+
+// missingPackages returns the names of the missing packages that contributed
+// to derive the incompatibility
+func (inc *incompatibility) missingPackages() []string {
+	found := map[string]bool{}
+	for _, ext := range inc.external() {
+		if ext.cause == causeMissing {
+			found[ext.terms[0].pkg] = true
+		}
+	}
+	var res []string
+	for pkg := range found {
+		res = append(res, pkg)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// assignment is a term in the partial solution, it may be a decision (if
+// cause is nil) or a derivation from an incompatibility
+type assignment struct {
+	term
+	level int
+	index int
+	cause *incompatibility
+}
+
+type relation int
+
+const (
+	satisfied relation = iota
+	contradicted
+	inconclusive
+	almostSatisfied
+)
+
+type pubgrubSolver[R Release[D], D Dependency] struct {
+	resolver     *resolution[R, D]
+	root         R
+	hasRoot      bool
+	requirements []*Requirement[R, D]
+	// rootIncompatibilities maps the incompatibilities derived from the
+	// root requirements to the requirement
+	rootIncompatibilities map[*incompatibility]*Requirement[R, D]
+
+	incompatibilities map[string][]*incompatibility
+	assignments       []*assignment
+	level             int
+	terms             map[string]term
+	decisions         map[string]R
+
+	releases         map[string]Releases[R, D]
+	dependenciesDone map[string]bool
+}
+
+// newPubgrubSolver creates a solver for the given root requirements, if the
+// resolution has a root release the requirements must be its dependencies.
+func newPubgrubSolver[R Release[D], D Dependency](resolver *resolution[R, D], requirements []*Requirement[R, D]) *pubgrubSolver[R, D] {
+	return &pubgrubSolver[R, D]{
+		resolver:              resolver,
+		root:                  resolver.root,
+		hasRoot:               resolver.hasRoot,
+		requirements:          requirements,
+		rootIncompatibilities: map[*incompatibility]*Requirement[R, D]{},
+		incompatibilities:     map[string][]*incompatibility{},
+		terms:                 map[string]term{},
+		decisions:             map[string]R{},
+		releases:              map[string]Releases[R, D]{},
+		dependenciesDone:      map[string]bool{},
+	}
+}
+
+// solve runs the PubGrub algorithm, if a solution is found it is returned,
+// otherwise the incompatibility that proves that no solution exists is
+// returned. If the resolution is aborted an error is returned.
+func (s *pubgrubSolver[R, D]) solve() (Releases[R, D], *incompatibility, error) {
+	var next []string
+	if s.hasRoot {
+		rootName := s.root.GetName()
+		s.addIncompatibility(newIncompatibility(
+			[]term{{pkg: rootName, set: versionPoint(s.root.GetVersion()), positive: false}},
+			causeRoot,
+			releaseString(s.root)+" is the release to resolve"))
+		next = append(next, rootName)
+	} else {
+		for _, req := range s.requirements {
+			dep := req.Dependency
+			set, err := s.dependencySet(dep)
+			if err != nil {
+				return nil, nil, err
+			}
+			inc := newIncompatibility(
+				[]term{{pkg: dep.GetName(), set: set, positive: false}},
+				causeRoot,
+				dependencyString(dep)+" is required")
+			s.addIncompatibility(inc)
+			s.rootIncompatibilities[inc] = req
+			next = append(next, dep.GetName())
+		}
+	}
+
+	for _, pkg := range next {
+		if failure, err := s.propagate(pkg); failure != nil || err != nil {
+			return nil, failure, err
+		}
+	}
+	for {
+		if err := s.resolver.step(s.level); err != nil {
+			return nil, nil, err
+		}
+		pkg, found, err := s.choosePackageVersion()
+		if err != nil {
+			return nil, nil, err
+		}
+		if !found {
+			break
+		}
+		if failure, err := s.propagate(pkg); failure != nil || err != nil {
+			return nil, failure, err
+		}
+	}
+	return s.solution(), nil, nil
+}
+
+// isRoot returns true if pkg is the package of the root release
+func (s *pubgrubSolver[R, D]) isRoot(pkg string) bool {
+	return s.hasRoot && pkg == s.root.GetName()
+}
+
+// failedRequirements returns the root requirements that contributed to
+// derive the failure
+func (s *pubgrubSolver[R, D]) failedRequirements(failure *incompatibility) []*Requirement[R, D] {
+	var res []*Requirement[R, D]
+	for _, inc := range failure.external() {
+		if req, ok := s.rootIncompatibilities[inc]; ok && !slices.Contains(res, req) {
+			res = append(res, req)
+		}
+	}
+	return res
+}
+
+// solution returns the releases decided so far
+func (s *pubgrubSolver[R, D]) solution() Releases[R, D] {
+	res := Releases[R, D]{}
+	for _, a := range s.assignments {
+		if a.cause == nil {
+			res = append(res, s.decisions[a.pkg])
+		}
+	}
+	return res
+}
+
+func (s *pubgrubSolver[R, D]) addIncompatibility(inc *incompatibility) {
+	debug("new incompatibility: %s", inc)
+	for _, t := range inc.terms {
+		s.incompatibilities[t.pkg] = append(s.incompatibilities[t.pkg], inc)
+	}
+}
+
+// propagate performs the unit propagation starting from the given package
+func (s *pubgrubSolver[R, D]) propagate(pkg string) (*incompatibility, error) {
+	changed := []string{pkg}
+	for len(changed) > 0 {
+		pkg := changed[len(changed)-1]
+		changed = changed[:len(changed)-1]
+
+		incs := s.incompatibilities[pkg]
+		for i := len(incs) - 1; i >= 0; i-- {
+			inc := incs[i]
+			rel, unsatisfied := s.relation(inc)
+			if rel == satisfied {
+				if err := s.resolver.step(s.level); err != nil {
+					return nil, err
+				}
+				rootCause, failure := s.resolveConflict(inc)
+				if failure != nil {
+					return failure, nil
+				}
+				_, unsatisfied = s.relation(rootCause)
+				s.derive(unsatisfied.negate(), rootCause)
+				changed = []string{unsatisfied.pkg}
+				break
+			}
+			if rel == almostSatisfied {
+				s.derive(unsatisfied.negate(), inc)
+				changed = append(changed, unsatisfied.pkg)
+			}
+		}
+	}
+	return nil, nil
+}
+
+// relation returns the relation between the incompatibility and the partial
+// solution, if the incompatibility is almost satisfied the unsatisfied term
+// is returned.
+func (s *pubgrubSolver[R, D]) relation(inc *incompatibility) (relation, term) {
+	var unsatisfied *term
+	for i, t := range inc.terms {
+		switch s.termRelation(t) {
+		case contradicted:
+			return contradicted, term{}
+		case inconclusive:
+			if unsatisfied != nil {
+				return inconclusive, term{}
+			}
+			unsatisfied = &inc.terms[i]
+		}
+	}
+	if unsatisfied == nil {
+		return satisfied, term{}
+	}
+	return almostSatisfied, *unsatisfied
+}
+
+func (s *pubgrubSolver[R, D]) termRelation(t term) relation {
+	assigned, ok := s.terms[t.pkg]
+	if !ok {
+		return inconclusive
+	}
+	if assigned.satisfies(t) {
+		return satisfied
+	}
+	if assigned.contradicts(t) {
+		return contradicted
+	}
+	return inconclusive
+}
+
+// resolveConflict performs the conflict resolution on the satisfied
+// incompatibility. It returns the incompatibility that will be almost
+// satisfied after backjumping, or the root cause of the failure if no
+// solution exists.
+func (s *pubgrubSolver[R, D]) resolveConflict(inc *incompatibility) (*incompatibility, *incompatibility) {
+	debug("conflict: %s", inc)
+	created := false
+	for len(inc.terms) > 0 {
+		var mostRecentTerm *term
+		var mostRecentSatisfier *assignment
+		var difference *term
+		previousSatisfierLevel := 0
+		for i := range inc.terms {
+			t := &inc.terms[i]
+			satisfier := s.satisfier(*t)
+			if mostRecentSatisfier == nil {
+				mostRecentTerm = t
+				mostRecentSatisfier = satisfier
+			} else if mostRecentSatisfier.index < satisfier.index {
+				previousSatisfierLevel = max(previousSatisfierLevel, mostRecentSatisfier.level)
+				mostRecentTerm = t
+				mostRecentSatisfier = satisfier
+				difference = nil
+			} else {
+				previousSatisfierLevel = max(previousSatisfierLevel, satisfier.level)
+			}
+
+			if mostRecentTerm == t {
+				// If mostRecentSatisfier doesn't satisfy mostRecentTerm on its own,
+				// the previous assignments that contributed must be considered
+				if diff := mostRecentSatisfier.term.difference(*t); !diff.isEmpty() {
+					difference = &diff
+					previousSatisfierLevel = max(previousSatisfierLevel, s.satisfier(diff.negate()).level)
+				} else {
+					difference = nil
+				}
+			}
+		}
+
+		if previousSatisfierLevel < mostRecentSatisfier.level || mostRecentSatisfier.cause == nil {
+			s.backtrack(previousSatisfierLevel)
+			if created {
+				s.addIncompatibility(inc)
+			}
+			return inc, nil
+		}
+
+		var terms []term
+		for i := range inc.terms {
+			if &inc.terms[i] != mostRecentTerm {
+				terms = append(terms, inc.terms[i])
+			}
+		}
+		for _, t := range mostRecentSatisfier.cause.terms {
+			if t.pkg != mostRecentSatisfier.pkg {
+				terms = append(terms, t)
+			}
+		}
+		if difference != nil {
+			terms = append(terms, difference.negate())
+		}
+		prior := newIncompatibility(terms, causeDerived, "")
+		prior.causes = [2]*incompatibility{inc, mostRecentSatisfier.cause}
+		debug("derived: %s", prior)
+		inc = prior
+		created = true
+	}
+	return nil, inc
+}
+
+// satisfier returns the earliest assignment such that the partial solution,
+// up to and including that assignment, satisfies the term
+func (s *pubgrubSolver[R, D]) satisfier(t term) *assignment {
+	var assigned *term
+	for _, a := range s.assignments {
+		if a.pkg != t.pkg {
+			continue
+		}
+		if assigned == nil {
+			assigned = &a.term
+		} else {
+			intersection := assigned.intersect(a.term)
+			assigned = &intersection
+		}
+		if assigned.satisfies(t) {
+			return a
+		}
+	}
+	panic("no satisfier found for " + t.String())
+}
+
+func (s *pubgrubSolver[R, D]) assign(a *assignment) {
+	a.index = len(s.assignments)
+	a.level = s.level
+	s.assignments = append(s.assignments, a)
+	if assigned, ok := s.terms[a.pkg]; ok {
+		s.terms[a.pkg] = assigned.intersect(a.term)
+	} else {
+		s.terms[a.pkg] = a.term
+	}
+}
+
+func (s *pubgrubSolver[R, D]) derive(t term, cause *incompatibility) {
+	debug("derived: %s (%s)", t, cause)
+	s.assign(&assignment{term: t, cause: cause})
+}
+
+func (s *pubgrubSolver[R, D]) decide(release R) {
+	debug("decision: %s", releaseString(release))
+	s.level++
+	s.decisions[release.GetName()] = release
+	s.assign(&assignment{term: term{pkg: release.GetName(), set: versionPoint(release.GetVersion()), positive: true}})
+	if len(s.decisions) > len(s.resolver.best) {
+		s.resolver.best = s.solution()
+	}
+}
+
+// backtrack removes all the assignments made after the given decision level
+func (s *pubgrubSolver[R, D]) backtrack(level int) {
+	debug("backtracking to level %d", level)
+	assignments := s.assignments
+	s.assignments = nil
+	s.terms = map[string]term{}
+	s.level = 0
+	for _, a := range assignments {
+		if a.level > level {
+			if a.cause == nil {
+				delete(s.decisions, a.pkg)
+			}
+			continue
+		}
+		if a.cause == nil {
+			s.level++
+		}
+		s.assign(a)
+	}
+}
+
+// candidates returns the releases of the package in the given set, ordered
+// by preference.
+func (s *pubgrubSolver[R, D]) candidates(pkg string, set versionSet) (Releases[R, D], error) {
+	if s.isRoot(pkg) {
+		if set.contains(s.root.GetVersion()) {
+			return Releases[R, D]{s.root}, nil
+		}
+		return nil, nil
+	}
+	releases, ok := s.releases[pkg]
+	if !ok {
+		available, err := s.resolver.releasesFor(pkg)
+		if err != nil {
+			return nil, err
+		}
+		releases = s.resolver.selectable(pkg, available)
+		releases.SortDescent()
+		s.releases[pkg] = releases
+	}
+	var res Releases[R, D]
+	for _, r := range releases {
+		if set.contains(r.GetVersion()) {
+			res = append(res, r)
+		}
+	}
+	s.resolver.sortCandidates(pkg, res)
+	return res, nil
+}
+
+// choosePackageVersion picks the next package to decide, if all the required
+// packages are decided it returns false.
+func (s *pubgrubSolver[R, D]) choosePackageVersion() (string, bool, error) {
+	var pkg string
+	var candidates Releases[R, D]
+	found := false
+	seen := map[string]bool{}
+	for _, a := range s.assignments {
+		if seen[a.pkg] {
+			continue
+		}
+		seen[a.pkg] = true
+		t := s.terms[a.pkg]
+		if !t.positive {
+			continue
+		}
+		if _, decided := s.decisions[a.pkg]; decided {
+			continue
+		}
+		// Pick the package with the fewest candidates first
+		c, err := s.candidates(a.pkg, t.set)
+		if err != nil {
+			return "", false, err
+		}
+		if !found || len(c) < len(candidates) {
+			pkg, candidates, found = a.pkg, c, true
+		}
+	}
+	if !found {
+		return "", false, nil
+	}
+
+	set := s.terms[pkg].set
+	if len(candidates) == 0 {
+		if len(s.releases[pkg]) == 0 && !s.isRoot(pkg) {
+			s.addIncompatibility(newIncompatibility(
+				[]term{{pkg: pkg, set: fullVersionSet(), positive: true}},
+				causeMissing,
+				pkg+" is missing from the archive"))
+		} else {
+			s.addIncompatibility(newIncompatibility(
+				[]term{{pkg: pkg, set: set, positive: true}},
+				causeNoVersions,
+				"no release of "+packageSetString(pkg, set)+" is available"))
+		}
+		return pkg, true, nil
+	}
+
+	release := candidates[0]
+	var incs []*incompatibility
+	for i, dep := range release.GetDependencies() {
+		releasesSet := s.dependencyRange(release, dep)
+		key := packageSetString(pkg, releasesSet) + " depends on " + dependencyString(dep)
+		if s.dependenciesDone[key] {
+			continue
+		}
+		depSet, err := s.dependencySet(dep)
+		if err != nil {
+			return "", false, err
+		}
+		s.dependenciesDone[key] = true
+		inc := newIncompatibility(
+			[]term{
+				{pkg: pkg, set: releasesSet, positive: true},
+				{pkg: dep.GetName(), set: depSet, positive: false},
+			},
+			causeDependency,
+			key)
+		s.addIncompatibility(inc)
+		if s.isRoot(pkg) {
+			s.rootIncompatibilities[inc] = s.requirements[i]
+		}
+		incs = append(incs, inc)
+	}
+
+	// Do not decide the release if it would immediately cause a conflict,
+	// the propagation will take care of excluding it.
+	for _, inc := range incs {
+		conflict := true
+		for _, t := range inc.terms {
+			if t.pkg != pkg && s.termRelation(t) != satisfied {
+				conflict = false
+				break
+			}
+		}
+		if conflict {
+			return pkg, true, nil
+		}
+	}
+	s.decide(release)
+	return pkg, true, nil
+}
+
+// dependencyRange returns the range of versions, around the given release,
+// where all the releases of the package have the same dependency. This allows
+// to derive incompatibilities that cover many releases at once.
+func (s *pubgrubSolver[R, D]) dependencyRange(release R, dep D) versionSet {
+	point := versionPoint(release.GetVersion())
+	if s.isRoot(release.GetName()) {
+		return point
+	}
+	hasDependency := func(r R) bool {
+		for _, d := range r.GetDependencies() {
+			if d.GetName() == dep.GetName() && d.GetConstraint().String() == dep.GetConstraint().String() {
+				return true
+			}
+		}
+		return false
+	}
+
+	// releases are sorted in descending order
+	releases := s.releases[release.GetName()]
+	idx := 0
+	for idx < len(releases) && !releases[idx].GetVersion().Equal(release.GetVersion()) {
+		idx++
+	}
+	if idx == len(releases) {
+		return point
+	}
+	first, last := idx, idx
+	for first > 0 && hasDependency(releases[first-1]) {
+		first--
+	}
+	for last+1 < len(releases) && hasDependency(releases[last+1]) {
+		last++
+	}
+	var lower, upper *Version
+	if last+1 < len(releases) {
+		lower = versionBound(releases[last].GetVersion())
+	}
+	if first > 0 {
+		upper = versionBound(releases[first-1].GetVersion())
+	}
+	if res := versionRange(lower, upper); res.contains(release.GetVersion()) {
+		return res
+	}
+	return point
+}
+
+// dependencySet returns the set of versions allowed by the dependency
+func (s *pubgrubSolver[R, D]) dependencySet(dep D) (versionSet, error) {
+	c := dep.GetConstraint()
+	matchCtx := s.resolver.matchCtx
+	set, ok := constraintToVersionSet(c)
+	if ok && (matchCtx == nil || !matchCtx.ExcludePrereleases) {
+		return set, nil
+	}
+	releases, err := s.resolver.releasesFor(dep.GetName())
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		// Remove the pre-releases excluded by the MatchContext
+		for _, r := range releases {
+			v := r.GetVersion()
+			if set.contains(v) && !matchCtx.Match(c, v) {
+				set = set.difference(versionPoint(v))
+			}
+		}
+		return set, nil
+	}
+	// The constraint can not be converted into a set of intervals, fallback to
+	// the set of the available versions matching the constraint.
+	set = versionSet{}
+	for _, r := range releases.FilterByContext(c, matchCtx) {
+		set = set.union(versionPoint(r.GetVersion()))
+	}
+	return set, nil
+}
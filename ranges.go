@@ -0,0 +1,207 @@
+//
+// Copyright 2018-2025 Cristian Maglie. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+
+package semver
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Range is an interval of versions. A nil Min means that the Range is
+// unbounded below, a nil Max means that the Range is unbounded above.
+type Range struct {
+	Min          *Version
+	MinInclusive bool
+	Max          *Version
+	MaxInclusive bool
+}
+
+// Contains returns true if v is inside the Range
+func (r Range) Contains(v *Version) bool {
+	return r.interval().contains(v)
+}
+
+// interval converts the Range into the equivalent half-open set
+func (r Range) interval() versionSet {
+	var lower, upper *Version
+	if r.Min != nil {
+		lower = versionBound(r.Min)
+		if !r.MinInclusive {
+			lower = successor(lower)
+		}
+	}
+	if r.Max != nil {
+		upper = versionBound(r.Max)
+		if r.MaxInclusive {
+			upper = successor(upper)
+		}
+	}
+	return versionRange(lower, upper)
+}
+
+func (r Range) String() string {
+	lower, upper := "(-inf", "+inf)"
+	if r.Min != nil {
+		lower = "(" + r.Min.String()
+		if r.MinInclusive {
+			lower = "[" + r.Min.String()
+		}
+	}
+	if r.Max != nil {
+		upper = r.Max.String() + ")"
+		if r.MaxInclusive {
+			upper = r.Max.String() + "]"
+		}
+	}
+	return lower + ", " + upper
+}
+
+// VersionSet is a set of versions, represented as an ordered list of
+// disjoint Ranges. The zero value is the empty set.
+type VersionSet struct {
+	set versionSet
+}
+
+// NewVersionSet returns the set of the versions matching the Constraint.
+// An error is returned if the Constraint is not defined in this package.
+func NewVersionSet(c Constraint) (VersionSet, error) {
+	set, ok := constraintToVersionSet(c)
+	if !ok {
+		return VersionSet{}, fmt.Errorf("unsupported constraint: %s", c)
+	}
+	return VersionSet{set}, nil
+}
+
+// NewVersionSetFromRanges returns the set of the versions contained in any
+// of the given Ranges.
+func NewVersionSetFromRanges(ranges ...Range) VersionSet {
+	res := versionSet{}
+	for _, r := range ranges {
+		res = res.union(r.interval())
+	}
+	return VersionSet{res}
+}
+
+// Ranges returns the ordered list of disjoint Ranges composing the set
+func (s VersionSet) Ranges() []Range {
+	var res []Range
+	for _, in := range s.set {
+		r := Range{}
+		if in.lower != nil {
+			if p := predecessor(in.lower); p != nil {
+				r.Min = p
+			} else {
+				r.Min, r.MinInclusive = in.lower, true
+			}
+		}
+		if in.upper != nil {
+			if p := predecessor(in.upper); p != nil {
+				r.Max, r.MaxInclusive = p, true
+			} else {
+				r.Max = in.upper
+			}
+		}
+		res = append(res, r)
+	}
+	return res
+}
+
+// Intersect returns the set of versions contained in both s and t
+func (s VersionSet) Intersect(t VersionSet) VersionSet {
+	return VersionSet{s.set.intersect(t.set)}
+}
+
+// Union returns the set of versions contained in s or in t
+func (s VersionSet) Union(t VersionSet) VersionSet {
+	return VersionSet{s.set.union(t.set)}
+}
+
+// Complement returns the set of versions not contained in s
+func (s VersionSet) Complement() VersionSet {
+	return VersionSet{s.set.complement()}
+}
+
+// Contains returns true if v is contained in the set
+func (s VersionSet) Contains(v *Version) bool {
+	return s.set.contains(v)
+}
+
+// IsEmpty returns true if the set does not contain any version
+func (s VersionSet) IsEmpty() bool {
+	return s.set.isEmpty()
+}
+
+// Equal returns true if s and t contain the same versions
+func (s VersionSet) Equal(t VersionSet) bool {
+	return s.set.equal(t.set)
+}
+
+// Constraint returns a Constraint matching the versions in the set
+func (s VersionSet) Constraint() Constraint {
+	return s.set.constraint()
+}
+
+func (s VersionSet) String() string {
+	if s.IsEmpty() {
+		return "{}"
+	}
+	var res []string
+	for _, r := range s.Ranges() {
+		res = append(res, r.String())
+	}
+	return strings.Join(res, " | ")
+}
+
+// IsSatisfiable returns true if at least one version matches the Constraint.
+// An error is returned if the Constraint is not defined in this package.
+func IsSatisfiable(c Constraint) (bool, error) {
+	s, err := NewVersionSet(c)
+	if err != nil {
+		return false, err
+	}
+	return !s.IsEmpty(), nil
+}
+
+// Implies returns true if all the versions matching a also match b, in that
+// case b is redundant when used in And with a.
+func Implies(a, b Constraint) (bool, error) {
+	s, t, err := newVersionSets(a, b)
+	if err != nil {
+		return false, err
+	}
+	return s.set.difference(t.set).isEmpty(), nil
+}
+
+// Equivalent returns true if a and b match the same versions
+func Equivalent(a, b Constraint) (bool, error) {
+	s, t, err := newVersionSets(a, b)
+	if err != nil {
+		return false, err
+	}
+	return s.Equal(t), nil
+}
+
+// Intersects returns true if at least one version matches both a and b
+func Intersects(a, b Constraint) (bool, error) {
+	s, t, err := newVersionSets(a, b)
+	if err != nil {
+		return false, err
+	}
+	return !s.Intersect(t).IsEmpty(), nil
+}
+
+func newVersionSets(a, b Constraint) (VersionSet, VersionSet, error) {
+	s, err := NewVersionSet(a)
+	if err != nil {
+		return VersionSet{}, VersionSet{}, err
+	}
+	t, err := NewVersionSet(b)
+	if err != nil {
+		return VersionSet{}, VersionSet{}, err
+	}
+	return s, t, nil
+}
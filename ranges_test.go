@@ -0,0 +1,162 @@
+//
+// Copyright 2018-2025 Cristian Maglie. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+
+package semver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionSet(t *testing.T) {
+	set := func(in string) VersionSet {
+		c, err := ParseConstraint(in)
+		require.NoError(t, err)
+		res, err := NewVersionSet(c)
+		require.NoError(t, err)
+		return res
+	}
+
+	caret := set("^1.2.0")
+	require.Equal(t, "[1.2.0, 2.0.0-0)", caret.String())
+	require.Equal(t, []Range{{Min: v("1.2.0"), MinInclusive: true, Max: v("2.0.0-0")}}, caret.Ranges())
+	require.True(t, caret.Contains(v("1.5.0")))
+	require.False(t, caret.Contains(v("2.0.0")))
+	require.Equal(t, "^1.2.0", caret.Constraint().String())
+
+	require.Equal(t, "(1.0.0, 2.0.0]", set(">1.0.0 && <=2.0.0").String())
+	require.Equal(t, "(-inf, 1.2.3) | (1.2.3, +inf)", set("!=1.2.3").String())
+	require.Equal(t, "(-inf, +inf)", set("").String())
+	require.Equal(t, "{}", set(">2.0.0 && <1.0.0").String())
+	require.True(t, set(">2.0.0 && <1.0.0").IsEmpty())
+	require.True(t, VersionSet{}.IsEmpty())
+
+	require.Equal(t, "[1.5.0, 2.0.0-0)", caret.Intersect(set(">=1.5.0")).String())
+	require.Equal(t, "[1.2.0, +inf)", caret.Union(set(">=1.5.0")).String())
+	require.Equal(t, "(-inf, 1.2.0) | [2.0.0-0, +inf)", caret.Complement().String())
+	require.True(t, caret.Intersect(caret.Complement()).IsEmpty())
+	require.True(t, caret.Union(caret.Complement()).Equal(set("")))
+	require.True(t, set("~1").Equal(set("^1.0.0")))
+	require.False(t, set("~1.2").Equal(set("^1.2.0")))
+	require.True(t, set("=1.2.3").Equal(set(">=1.2.3 && <=1.2.3+build")))
+
+	fromRanges := NewVersionSetFromRanges(
+		Range{Min: v("1.0.0"), MinInclusive: true, Max: v("1.5.0")},
+		Range{Min: v("1.2.0"), Max: v("2.0.0"), MaxInclusive: true},
+		Range{Min: v("3.0.0")},
+	)
+	require.Equal(t, "[1.0.0, 2.0.0] | (3.0.0, +inf)", fromRanges.String())
+	require.Equal(t, "((>=1.0.0 && <=2.0.0) || >3.0.0)", fromRanges.Constraint().String())
+	require.True(t, NewVersionSetFromRanges().IsEmpty())
+	require.True(t, NewVersionSetFromRanges(Range{}).Equal(set("")))
+	require.True(t, NewVersionSetFromRanges(Range{Min: v("2.0.0"), Max: v("1.0.0")}).IsEmpty())
+
+	r := Range{Min: v("1.0.0"), Max: v("2.0.0"), MaxInclusive: true}
+	require.False(t, r.Contains(v("1.0.0")))
+	require.True(t, r.Contains(v("1.0.1-rc")))
+	require.True(t, r.Contains(v("2.0.0")))
+	require.False(t, r.Contains(v("2.0.1-0")))
+
+	_, err := NewVersionSet(&customConstraint{})
+	require.Error(t, err)
+}
+
+func TestConstraintRelations(t *testing.T) {
+	c := func(in string) Constraint {
+		res, err := ParseConstraint(in)
+		require.NoError(t, err)
+		return res
+	}
+	check := func(f func(a, b Constraint) (bool, error), a, b string) bool {
+		res, err := f(c(a), c(b))
+		require.NoError(t, err)
+		return res
+	}
+	satisfiable := func(in string) bool {
+		res, err := IsSatisfiable(c(in))
+		require.NoError(t, err)
+		return res
+	}
+
+	require.True(t, satisfiable(""))
+	require.True(t, satisfiable(">=1.0.0 && <1.0.1-0"))
+	require.True(t, satisfiable("^1.0.0 && !=1.5.0"))
+	require.False(t, satisfiable(">2.0.0 && <1.0.0"))
+	require.False(t, satisfiable(">=1.0.0 && <1.0.0"))
+	require.False(t, satisfiable("=1.0.0 && !=1.0.0"))
+	require.False(t, satisfiable("^1.2.0 && (<1.0.0 || >=2.0.0-0)"))
+
+	require.True(t, check(Implies, "=1.2.3", "^1.0.0"))
+	require.True(t, check(Implies, "~1.2.3", "^1.2.0"))
+	require.True(t, check(Implies, "1.2.x", ">=1.0.0"))
+	require.True(t, check(Implies, ">2.0.0 && <1.0.0", "=5.0.0"))
+	require.True(t, check(Implies, "^1.0.0", ""))
+	require.False(t, check(Implies, "^1.0.0", "~1.0.0"))
+	require.False(t, check(Implies, "", "^1.0.0"))
+
+	require.True(t, check(Equivalent, "~1", "^1.0.0"))
+	require.True(t, check(Equivalent, "!(<1.0.0 || >=2.0.0-0)", "^1.0.0"))
+	require.True(t, check(Equivalent, "1.0.0 - 2.0", ">=1.0.0 && <2.1.0-0"))
+	require.False(t, check(Equivalent, "~1.2.0", "^1.2.0"))
+
+	require.True(t, check(Intersects, "^1.0.0", "~1.5.0"))
+	require.True(t, check(Intersects, "<=1.0.0", ">=1.0.0"))
+	require.False(t, check(Intersects, "<1.0.0", ">=1.0.0"))
+	require.False(t, check(Intersects, "^1.0.0", "^2.0.0"))
+
+	_, err := IsSatisfiable(&customConstraint{})
+	require.Error(t, err)
+	_, err = Implies(c("^1.0.0"), &customConstraint{})
+	require.Error(t, err)
+	_, err = Equivalent(&customConstraint{}, c("^1.0.0"))
+	require.Error(t, err)
+	_, err = Intersects(&customConstraint{}, c("^1.0.0"))
+	require.Error(t, err)
+}
+
+func TestVersionSetWithFourComponents(t *testing.T) {
+	four := func(in string) *Version {
+		res, err := ParseWithOptions(in, ParseOptions{AllowFourComponents: true})
+		require.NoError(t, err)
+		return res
+	}
+	set := func(c Constraint) VersionSet {
+		res, err := NewVersionSet(c)
+		require.NoError(t, err)
+		return res
+	}
+
+	// The sets must agree with Match
+	for _, c := range []Constraint{
+		&Equals{v("1.2.3")},
+		&Equals{four("1.2.3.4")},
+		&GreaterThan{v("1.2.3")},
+		&LessThanOrEqual{v("1.2.3")},
+		&GreaterThan{four("1.2.3.4")},
+		&LessThan{four("1.2.3.4")},
+		&CompatibleWith{four("1.2.3.4")},
+		&HyphenRange{v("1.0.0"), v("1.2.3")},
+	} {
+		s := set(c)
+		for _, in := range []string{"1.2.3", "1.2.3.1-rc", "1.2.3.4", "1.2.3.4-rc", "1.2.3.5", "1.2.4-0", "1.2.4"} {
+			require.Equal(t, c.Match(four(in)), s.Contains(four(in)), "%s %s", c, in)
+		}
+	}
+	require.Equal(t, "[1.2.3, 1.2.3]", set(&Equals{v("1.2.3")}).String())
+	require.Equal(t, "=1.2.3.4", Simplify(&Equals{four("1.2.3.4")}).String())
+	require.Equal(t, ">1.2.3.4", Simplify(&GreaterThan{four("1.2.3.4")}).String())
+
+	implies, err := Implies(&Equals{four("1.2.3.4")}, &Equals{v("1.2.3")})
+	require.NoError(t, err)
+	require.False(t, implies)
+	equivalent, err := Equivalent(&Equals{four("1.2.3.4")}, &Equals{v("1.2.3")})
+	require.NoError(t, err)
+	require.False(t, equivalent)
+	implies, err = Implies(&Equals{four("1.2.3.4")}, &CompatibleWith{v("1.2.0")})
+	require.NoError(t, err)
+	require.True(t, implies)
+}
@@ -6,13 +6,18 @@
 
 package semver
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // RelaxedVersion allows any possible version string. If the version does not comply
 // with semantic versioning it is saved as-is and only Equal comparison will match.
 type RelaxedVersion struct {
 	customversion []byte
 	version       *Version
+	coerced       *Version
+	ordering      CustomOrdering
 }
 
 // WarnInvalidVersionWhenParsingRelaxed must be set to true to show warnings while
@@ -20,7 +25,8 @@ type RelaxedVersion struct {
 // transition to strict semver
 var WarnInvalidVersionWhenParsingRelaxed = false
 
-// ParseRelaxed parse a RelaxedVersion
+// ParseRelaxed parse a RelaxedVersion, the custom versions are compared with
+// the DefaultCustomOrdering.
 func ParseRelaxed(in string) *RelaxedVersion {
 	v, err := Parse(in)
 	if err == nil {
@@ -29,7 +35,37 @@ func ParseRelaxed(in string) *RelaxedVersion {
 	if WarnInvalidVersionWhenParsingRelaxed {
 		fmt.Printf("WARNING invalid semver version %s: %s\n", in, err)
 	}
-	return &RelaxedVersion{customversion: []byte(in[:])}
+	return &RelaxedVersion{customversion: []byte(in[:]), ordering: DefaultCustomOrdering}
+}
+
+// RelaxedOptions are the options of ParseRelaxedWithOptions
+type RelaxedOptions struct {
+	// Coerce enables the coerced ordering of the versions that do not comply
+	// with semver: the version obtained with Coerce is used for comparison
+	// while String() still returns the original string. A coerced version is
+	// greater than the semver version it is coerced to, and two versions
+	// coerced to the same semver are compared with the Ordering. Versions
+	// that can not be coerced are compared as in ParseRelaxed.
+	Coerce bool
+
+	// Ordering is the ordering used to compare the custom versions, if not
+	// set the DefaultCustomOrdering is used. Versions parsed with different
+	// orderings should not be compared with each other.
+	Ordering CustomOrdering
+}
+
+// ParseRelaxedWithOptions parse a RelaxedVersion with the given options.
+// The JSON, YAML and SQL decoders use ParseRelaxed, so the decoded versions
+// are not coerced and use the DefaultCustomOrdering.
+func ParseRelaxedWithOptions(in string, opts RelaxedOptions) *RelaxedVersion {
+	res := ParseRelaxed(in)
+	if res.version == nil {
+		res.ordering = opts.Ordering.resolve()
+		if opts.Coerce {
+			res.coerced, _ = Coerce(in)
+		}
+	}
+	return res
 }
 
 func (v *RelaxedVersion) String() string {
@@ -59,16 +95,41 @@ func (v *RelaxedVersion) NormalizedString() NormalizedString {
 // Returns -1, 0 or 1 if the version is respectively less than, equal
 // or greater than the compared Version
 func (v *RelaxedVersion) CompareTo(u *RelaxedVersion) int {
-	if v.version == nil && u.version == nil {
-		return compareAlpha(v.customversion, u.customversion)
+	vVersion, uVersion := v.version, u.version
+	if vVersion == nil {
+		vVersion = v.coerced
 	}
-	if v.version == nil {
+	if uVersion == nil {
+		uVersion = u.coerced
+	}
+	if vVersion == nil && uVersion == nil {
+		return v.compareCustom(u)
+	}
+	if vVersion == nil {
 		return -1
 	}
-	if u.version == nil {
+	if uVersion == nil {
 		return 1
 	}
-	return v.version.CompareTo(u.version)
+	if res := vVersion.CompareTo(uVersion); res != 0 {
+		return res
+	}
+	// Coerced versions follow the semver version they are coerced to
+	if v.version != nil && u.version != nil {
+		return 0
+	}
+	if v.version != nil {
+		return -1
+	}
+	if u.version != nil {
+		return 1
+	}
+	return v.compareCustom(u)
+}
+
+// compareCustom compares two custom versions with their ordering
+func (v *RelaxedVersion) compareCustom(u *RelaxedVersion) int {
+	return strings.Compare(customKey(v.customversion, v.ordering), customKey(u.customversion, u.ordering))
 }
 
 // LessThan returns true if the RelaxedVersion is less than the RelaxedVersion passed as parameter
@@ -116,7 +177,12 @@ func (v *RelaxedVersion) SortableString() string {
 	if v.version != nil {
 		return v.version.SortableString()
 	}
-	return ":" + string(v.customversion)
+	if v.coerced != nil {
+		// The space sorts before any character that may follow the encoded
+		// version, so that the coerced version is placed right after it.
+		return v.coerced.SortableString() + " " + customKey(v.customversion, v.ordering)
+	}
+	return ":" + customKey(v.customversion, v.ordering)
 }
 
 // IsPrerelease returns true if the version is valid semver and has a pre-release part
@@ -137,6 +137,86 @@ func TestRelaxedVersionComparator(t *testing.T) {
 		ParseRelaxed("0.0+aaa.bbb"),
 		ParseRelaxed("0.0.0+aaa.bbb"),
 	)
+
+	coerced := func(in string) *RelaxedVersion {
+		return ParseRelaxedWithOptions(in, RelaxedOptions{Coerce: true})
+	}
+	ascending(
+		ParseRelaxed("2.0.0.beta3"),
+		coerced("alpha"),
+		coerced("beta"),
+		coerced("1.0.0-alpha"),
+		coerced("1.0.0.alpha"),
+		coerced("1.0.0-alpha.1"),
+		coerced("1.0.0"),
+		coerced("1.0.0.1"),
+		coerced("v1.0"),
+		coerced("1.0.1"),
+		coerced("release-1.4_final"),
+		coerced("release-1.4_preview"),
+		coerced("2.0.0.beta3"),
+		ParseRelaxed("2.0.0"),
+	)
+	require.Equal(t, "2.0.0.beta3", coerced("2.0.0.beta3").String())
+
+	natural := func(in string) *RelaxedVersion {
+		return ParseRelaxedWithOptions(in, RelaxedOptions{Ordering: OrderingNatural})
+	}
+	ascending(
+		natural("1_9"),
+		natural("1_10"),
+		natural("build-9"),
+		natural("build-10"),
+		natural("build-10a"),
+		natural("build-010"),
+		natural("build-10000"),
+		natural("build-99999999999999999999"),
+		natural("build-100000000000000000000"),
+		natural("build-x"),
+		ParseRelaxedWithOptions("release-1.4_rc9", RelaxedOptions{Coerce: true, Ordering: OrderingNatural}),
+		ParseRelaxedWithOptions("release-1.4_rc10", RelaxedOptions{Coerce: true, Ordering: OrderingNatural}),
+	)
+
+	debian := func(in string) *RelaxedVersion {
+		return ParseRelaxedWithOptions(in, RelaxedOptions{Ordering: OrderingDebian})
+	}
+	ascending(
+		debian("0:1.0~rc1~1"),
+		debian("0:1.0~rc1"),
+		debian("0:1.0"),
+		debian("0:1.0-1"),
+		debian("0:1.0-1ubuntu1"),
+		debian("0:1.0a"),
+		debian("0:1.0+b"),
+		debian("0:1.0.1"),
+		debian("0:1.0.10"),
+		debian("1:0.9"),
+	)
+	equal(
+		debian("0:1.01"),
+		debian("0:1.1"),
+		debian("01.1"),
+		debian("0:01.1-0"),
+	)
+
+	DefaultCustomOrdering = OrderingNatural
+	defer func() { DefaultCustomOrdering = OrderingAlphabetical }()
+	ascending(
+		ParseRelaxed("build-9"),
+		ParseRelaxed("build-10"),
+	)
+	ascending(
+		ParseRelaxedWithOptions("build-10", RelaxedOptions{Ordering: OrderingAlphabetical}),
+		ParseRelaxedWithOptions("build-9", RelaxedOptions{Ordering: OrderingAlphabetical}),
+	)
+
+	// The zero value follows the DefaultCustomOrdering
+	zero := func(in string) *RelaxedVersion {
+		return &RelaxedVersion{customversion: []byte(in)}
+	}
+	ascending(zero("build-9"), zero("build-10"))
+	DefaultCustomOrdering = OrderingAlphabetical
+	ascending(zero("build-10"), zero("build-9"))
 }
 
 func TestRelaxedCompatibleWith(t *testing.T) {
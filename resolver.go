@@ -6,7 +6,15 @@
 
 package semver
 
-import "sort"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"sync"
+)
 
 // Dependency represents a dependency, it must provide methods to return Name and Constraints
 type Dependency interface {
@@ -27,9 +35,15 @@ type Releases[R Release[D], D Dependency] []R
 
 // FilterBy return a subset of the Releases matching the provided Constraint
 func (set Releases[R, D]) FilterBy(c Constraint) Releases[R, D] {
+	return set.FilterByContext(c, nil)
+}
+
+// FilterByContext return a subset of the Releases matching the provided
+// Constraint in the given MatchContext
+func (set Releases[R, D]) FilterByContext(c Constraint, ctx *MatchContext) Releases[R, D] {
 	var res Releases[R, D]
 	for _, r := range set {
-		if c.Match(r.GetVersion()) {
+		if ctx.Match(c, r.GetVersion()) {
 			res = append(res, r)
 		}
 	}
@@ -44,15 +58,116 @@ func (set Releases[R, D]) SortDescent() {
 	})
 }
 
+// SortAscent sort the Releases in this set in ascending order (the oldest
+// release is the first)
+func (set Releases[R, D]) SortAscent() {
+	sort.Slice(set, func(i, j int) bool {
+		return set[i].GetVersion().LessThan(set[j].GetVersion())
+	})
+}
+
+// CandidateOrder sorts in place the candidate releases of the package with the
+// given name, the Resolver tries the candidates in the resulting order.
+type CandidateOrder[R Release[D], D Dependency] func(name string, candidates Releases[R, D])
+
+// Algorithm is the algorithm used by a Resolver to search for a solution
+type Algorithm int
+
+const (
+	// Backtracking tries all the matching releases of each dependency, starting
+	// from the latest, until a solution is found. It is the default algorithm.
+	Backtracking Algorithm = iota
+	// PubGrub is a conflict-driven solver that learns from each conflict to
+	// avoid exploring the same dead ends again, it's faster than Backtracking
+	// on large and intricate archives. On failure it gives a detailed explanation
+	// of the reason in the Explanation field of the ResolutionError.
+	PubGrub
+	// MinimalVersionSelection selects, for each package, the minimum version
+	// satisfying all the requirements, like Go modules do. The constraints
+	// are used as lower bounds, the upper bounds are only checked on the final
+	// selection: a constraint that is not a single range of versions (for
+	// example "<1.0.0 || >2.0.0") causes an error wrapping ErrNotLowerBound.
+	// The preferred versions are ignored.
+	MinimalVersionSelection
+)
+
 // Resolver is a container with references to all Releases to consider for
-// dependency resolution
+// dependency resolution. A Resolver may be used concurrently by multiple
+// goroutines, Releases may be added while other resolutions are in progress:
+// each resolution works on a snapshot of the archive taken at its start.
 type Resolver[R Release[D], D Dependency] struct {
-	releases map[string]Releases[R, D]
+	mutex     sync.Mutex
+	releases  map[string]Releases[R, D]
+	snapshot  map[string]Releases[R, D]
+	provider  ReleaseProvider[R, D]
+	algorithm Algorithm
+	maxSteps  int
+	maxDepth  int
+	order     CandidateOrder[R, D]
+	matchCtx  *MatchContext
 
-	// resolver state
+	locked      map[string]*Version
+	preferred   map[string]*Version
+	upgradeOnly []string
+}
+
+// resolution is the state of a single dependency resolution
+type resolution[R Release[D], D Dependency] struct {
+	archive   map[string]Releases[R, D]
+	provider  ReleaseProvider[R, D]
+	releases  map[string]Releases[R, D]
+	algorithm Algorithm
+	maxSteps  int
+	maxDepth  int
+	order     CandidateOrder[R, D]
+	matchCtx  *MatchContext
+	locked    map[string]*Version
+	preferred map[string]*Version
+
+	root         R
+	hasRoot      bool
+	requirements []D
+	rootPathLen  int
+
+	ctx             context.Context
+	steps           int
+	depth           int
+	best            Releases[R, D]
+	bestSize        int
+	bestStale       bool
 	solution        map[string]R
-	depsToProcess   []D
+	selectedBy      map[string]*Requirement[R, D]
+	depsToProcess   []*Requirement[R, D]
 	problematicDeps map[dependencyHash]int
+	conflicts       map[conflictKey]*conflictRecord[R, D]
+	canonicalKeys   map[dependencyHash]dependencyHash
+	missing         map[string]bool
+	missingRoots    map[dependencyHash]*Requirement[R, D]
+	dependencies    map[dependenciesKey[R, D]][]*Requirement[R, D]
+}
+
+// dependenciesKey identifies the release, by its Version, selected for a
+// Requirement
+type dependenciesKey[R Release[D], D Dependency] struct {
+	req     *Requirement[R, D]
+	version *Version
+}
+
+// conflictKey identifies a conflict by package and by the canonical hashes
+// of the requirements involved
+type conflictKey struct {
+	pkg              string
+	selectedBy, hash dependencyHash
+}
+
+// ReleaseProvider provides the Releases of the packages to a Resolver, it
+// allows to load the releases lazily, only for the packages actually
+// considered during the resolution.
+type ReleaseProvider[R Release[D], D Dependency] interface {
+	// ReleasesFor returns all the Releases of the package with the given name,
+	// an empty list must be returned if the package is not available.
+	// The returned Releases must not be modified afterwards.
+	ReleasesFor(name string) (Releases[R, D], error)
 }
 
 // NewResolver creates a new archive
@@ -62,83 +177,514 @@ func NewResolver[R Release[D], D Dependency]() *Resolver[R, D] {
 	}
 }
 
+// NewResolverWithProvider creates a new archive that queries the provider for
+// the Releases of each package. The provider is queried at most once per
+// package in each resolution. The Releases added with AddRelease are merged
+// with the ones returned by the provider. If the provider fails the resolution
+// is aborted with an *AbortedError wrapping the error of the provider.
+// The provider may be called concurrently by multiple resolutions.
+func NewResolverWithProvider[R Release[D], D Dependency](provider ReleaseProvider[R, D]) *Resolver[R, D] {
+	return &Resolver[R, D]{
+		releases: map[string]Releases[R, D]{},
+		provider: provider,
+	}
+}
+
 // AddRelease adds a release to this archive
 func (ar *Resolver[R, D]) AddRelease(rel R) {
-	relName := rel.GetName()
-	ar.releases[relName] = append(ar.releases[relName], rel)
+	ar.AddReleases(rel)
 }
 
 // AddReleases adds all the releases to this archive
 func (ar *Resolver[R, D]) AddReleases(rels ...R) {
+	ar.mutex.Lock()
+	defer ar.mutex.Unlock()
 	for _, rel := range rels {
 		relName := rel.GetName()
 		ar.releases[relName] = append(ar.releases[relName], rel)
 	}
+	ar.snapshot = nil
+}
+
+// ReleasesFor returns the Releases of the package added to this archive with
+// AddRelease, it implements the ReleaseProvider interface.
+func (ar *Resolver[R, D]) ReleasesFor(name string) (Releases[R, D], error) {
+	ar.mutex.Lock()
+	defer ar.mutex.Unlock()
+	return slices.Clip(ar.releases[name]), nil
+}
+
+// SetAlgorithm sets the algorithm used to resolve the dependencies, the
+// default is Backtracking.
+func (ar *Resolver[R, D]) SetAlgorithm(algorithm Algorithm) {
+	ar.mutex.Lock()
+	defer ar.mutex.Unlock()
+	ar.algorithm = algorithm
+}
+
+// SetMaxSteps sets the maximum number of steps that the resolver may perform
+// before giving up, a value of 0 means no limit (the default).
+// For the Backtracking and MinimalVersionSelection algorithms a step is the
+// processing of a dependency, for the PubGrub algorithm a step is a decision
+// or a conflict resolution.
+func (ar *Resolver[R, D]) SetMaxSteps(steps int) {
+	ar.mutex.Lock()
+	defer ar.mutex.Unlock()
+	ar.maxSteps = steps
+}
+
+// SetMaxDepth sets the maximum depth that the resolver may reach before
+// giving up, a value of 0 means no limit (the default).
+// For the Backtracking algorithm the depth is the recursion depth, for the
+// PubGrub algorithm the depth is the number of nested decisions, for the
+// MinimalVersionSelection algorithm the depth is the length of the chain of
+// releases requiring a dependency.
+func (ar *Resolver[R, D]) SetMaxDepth(depth int) {
+	ar.mutex.Lock()
+	defer ar.mutex.Unlock()
+	ar.maxDepth = depth
+}
+
+// SetCandidateOrder sets the function used to sort the candidate releases of
+// each package, the default (or if order is nil) is to try the latest release
+// first. The preferred versions are tried first regardless of the order.
+// The order is not used by the MinimalVersionSelection algorithm.
+func (ar *Resolver[R, D]) SetCandidateOrder(order CandidateOrder[R, D]) {
+	ar.mutex.Lock()
+	defer ar.mutex.Unlock()
+	ar.order = order
+}
+
+// SetMatchContext sets the MatchContext used to match the releases against
+// the constraints of the dependencies, for example to exclude pre-releases
+// not explicitly requested. The default (or if ctx is nil) is to use
+// Constraint.Match.
+func (ar *Resolver[R, D]) SetMatchContext(ctx *MatchContext) {
+	ar.mutex.Lock()
+	defer ar.mutex.Unlock()
+	if ctx != nil {
+		ctxCopy := *ctx
+		ctx = &ctxCopy
+	}
+	ar.matchCtx = ctx
+}
+
+// SetLockedVersions sets the versions of the packages that must be kept, for
+// example the versions recorded in a lock file. The resolver first tries to
+// find a solution using only the locked versions of these packages, if it is
+// not possible the locked versions are considered just as preferred versions.
+func (ar *Resolver[R, D]) SetLockedVersions(locked map[string]*Version) {
+	ar.mutex.Lock()
+	defer ar.mutex.Unlock()
+	ar.locked = maps.Clone(locked)
+}
+
+// SetPreferredVersions sets the versions of the packages that must be tried
+// first, before the other releases of the same package.
+func (ar *Resolver[R, D]) SetPreferredVersions(preferred map[string]*Version) {
+	ar.mutex.Lock()
+	defer ar.mutex.Unlock()
+	ar.preferred = maps.Clone(preferred)
+}
+
+// SetUpgradeOnly sets the packages that may be upgraded: the locked and
+// preferred versions of the given packages are ignored, so they are resolved
+// to the latest possible release, while the locked versions of all the other
+// packages are kept (unless impossible). Call it without arguments to clear
+// the list.
+func (ar *Resolver[R, D]) SetUpgradeOnly(names ...string) {
+	ar.mutex.Lock()
+	defer ar.mutex.Unlock()
+	ar.upgradeOnly = slices.Clone(names)
+}
+
+// newResolution creates the state for a new resolution, using a snapshot
+// of the archive and the current settings of the Resolver.
+func (ar *Resolver[R, D]) newResolution(ctx context.Context) *resolution[R, D] {
+	ar.mutex.Lock()
+	defer ar.mutex.Unlock()
+	if ar.snapshot == nil {
+		// The slices are clipped so that the releases appended later by
+		// AddReleases will not be visible through the snapshot.
+		ar.snapshot = make(map[string]Releases[R, D], len(ar.releases))
+		for name, releases := range ar.releases {
+			ar.snapshot[name] = slices.Clip(releases)
+		}
+	}
+	res := &resolution[R, D]{
+		archive:   ar.snapshot,
+		provider:  ar.provider,
+		releases:  map[string]Releases[R, D]{},
+		algorithm: ar.algorithm,
+		maxSteps:  ar.maxSteps,
+		maxDepth:  ar.maxDepth,
+		order:     ar.order,
+		matchCtx:  ar.matchCtx,
+		locked:    map[string]*Version{},
+		preferred: map[string]*Version{},
+		ctx:       ctx,
+
+		dependencies: map[dependenciesKey[R, D]][]*Requirement[R, D]{},
+	}
+	maps.Copy(res.locked, ar.locked)
+	maps.Copy(res.preferred, ar.preferred)
+	for _, name := range ar.upgradeOnly {
+		delete(res.locked, name)
+		delete(res.preferred, name)
+	}
+	res.reset()
+	return res
+}
+
+// reset clears the state of the search
+func (r *resolution[R, D]) reset() {
+	r.saveBest()
+	r.depth = 0
+	r.solution = map[string]R{}
+	r.selectedBy = map[string]*Requirement[R, D]{}
+	r.depsToProcess = []*Requirement[R, D]{}
+	r.problematicDeps = map[dependencyHash]int{}
+	r.conflicts = map[conflictKey]*conflictRecord[R, D]{}
+	r.canonicalKeys = map[dependencyHash]dependencyHash{}
+	r.missing = map[string]bool{}
+	r.missingRoots = map[dependencyHash]*Requirement[R, D]{}
 }
 
 // Resolve will try to depp-resolve dependencies from the Release passed as
-// arguent using a backtracking algorithm. This function is NOT thread-safe.
+// arguent using the algorithm set with SetAlgorithm. If no solution is found
+// nil is returned, use ResolveWithReport to know the reason of the failure.
 func (ar *Resolver[R, D]) Resolve(release R) Releases[R, D] {
-	// Initial empty state of the resolver
-	ar.solution = map[string]R{}
-	ar.depsToProcess = []D{}
-	ar.problematicDeps = map[dependencyHash]int{}
+	res, _ := ar.ResolveWithReport(release)
+	return res
+}
+
+// ResolveWithReport works like Resolve but, if no solution is found, it returns
+// an error describing the reason of the failure. If the release is not part of
+// the archive an error wrapping ErrReleaseNotFound is returned, if a limit set
+// on the Resolver is hit an *AbortedError is returned, otherwise the error is
+// a *ResolutionError.
+func (ar *Resolver[R, D]) ResolveWithReport(release R) (Releases[R, D], error) {
+	return ar.ResolveContext(context.Background(), release)
+}
+
+// ResolveContext works like ResolveWithReport but the resolution is aborted
+// when the context is done, in this case an *AbortedError wrapping the
+// context error is returned.
+func (ar *Resolver[R, D]) ResolveContext(ctx context.Context, release R) (Releases[R, D], error) {
+	return ar.newResolution(ctx).run(release)
+}
+
+// ResolveAll will try to deep-resolve all the given dependencies together, the
+// dependencies are the top-level requirements of the resolution (there is no
+// root release). If no solution is found an error is returned: an
+// *AbortedError if a limit set on the Resolver is hit or a *ResolutionError
+// reporting the top-level requirements that caused the failure.
+func (ar *Resolver[R, D]) ResolveAll(deps ...D) (Releases[R, D], error) {
+	return ar.ResolveAllContext(context.Background(), deps...)
+}
+
+// ResolveAllContext works like ResolveAll but the resolution is aborted when
+// the context is done, in this case an *AbortedError wrapping the context
+// error is returned.
+func (ar *Resolver[R, D]) ResolveAllContext(ctx context.Context, deps ...D) (Releases[R, D], error) {
+	return ar.newResolution(ctx).runAll(deps)
+}
+
+func (r *resolution[R, D]) run(release R) (Releases[R, D], error) {
+	r.root = release
+	r.hasRoot = true
+	r.rootPathLen = 1
 
 	// Check if the release is in the archive
-	if len(ar.releases[release.GetName()].FilterBy(&Equals{Version: release.GetVersion()})) == 0 {
-		return nil
+	available, err := r.releasesFor(release.GetName())
+	if err != nil {
+		return nil, r.aborted(err)
+	}
+	if len(available.FilterBy(&Equals{Version: release.GetVersion()})) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrReleaseNotFound, releaseString(release))
+	}
+
+	return r.solve(requirementsOf(release, nil))
+}
+
+func (r *resolution[R, D]) runAll(deps []D) (Releases[R, D], error) {
+	r.requirements = append([]D{}, deps...)
+	var reqs []*Requirement[R, D]
+	for _, dep := range deps {
+		reqs = append(reqs, &Requirement[R, D]{Dependency: dep})
+	}
+	return r.solve(reqs)
+}
+
+// solve resolves the top-level requirements, keeping the locked versions
+// unless impossible
+func (r *resolution[R, D]) solve(reqs []*Requirement[R, D]) (Releases[R, D], error) {
+	res, err := r.search(reqs)
+	var resErr *ResolutionError[R, D]
+	if errors.As(err, &resErr) && len(r.locked) > 0 {
+		debug("resolution with locked versions failed, retrying with locked versions as preferred")
+		for name, version := range r.locked {
+			if _, has := r.preferred[name]; !has {
+				r.preferred[name] = version
+			}
+		}
+		r.locked = nil
+		r.reset()
+		res, err = r.search(reqs)
+	}
+	return res, err
+}
+
+// search resolves the top-level requirements with the selected algorithm
+func (r *resolution[R, D]) search(reqs []*Requirement[R, D]) (Releases[R, D], error) {
+	if r.algorithm == MinimalVersionSelection {
+		return r.minimalVersionSelection(reqs)
+	}
+	if r.algorithm == PubGrub {
+		solver := newPubgrubSolver(r, reqs)
+		res, failure, err := solver.solve()
+		if err != nil {
+			return nil, r.aborted(err)
+		}
+		if failure != nil {
+			return nil, &ResolutionError[R, D]{
+				Root:               r.root,
+				Requirements:       r.requirements,
+				FailedRequirements: solver.failedRequirements(failure),
+				Missing:            failure.missingPackages(),
+				Explanation:        failure.explain(),
+			}
+		}
+		return res, nil
 	}
 
 	// Add the requested release to the solution and proceed
 	// with the dependencies resolution
-	ar.solution[release.GetName()] = release
-	ar.depsToProcess = append(ar.depsToProcess, release.GetDependencies()...)
-	return ar.resolve()
+	if r.hasRoot {
+		r.solution[r.root.GetName()] = r.root
+	}
+	r.depsToProcess = append(r.depsToProcess, reqs...)
+	r.updateBest()
+	res, err := r.resolve()
+	if err != nil {
+		return nil, r.aborted(err)
+	}
+	if res != nil {
+		return res, nil
+	}
+	return nil, r.report()
+}
+
+// releasesFor returns the Releases of the package, querying the provider the
+// first time the package is requested.
+func (r *resolution[R, D]) releasesFor(name string) (Releases[R, D], error) {
+	if res, ok := r.releases[name]; ok {
+		return res, nil
+	}
+	res := r.archive[name]
+	if r.provider != nil {
+		provided, err := r.provider.ReleasesFor(name)
+		if err != nil {
+			return nil, fmt.Errorf("fetching releases of %s: %w", name, err)
+		}
+		res = append(slices.Clip(res), provided...)
+	}
+	r.releases[name] = res
+	return res, nil
+}
+
+// selectable returns the releases of the package that may be selected: if
+// the package is locked only the release with the locked version.
+// The returned Releases are a copy that may be modified.
+func (r *resolution[R, D]) selectable(name string, releases Releases[R, D]) Releases[R, D] {
+	locked, ok := r.locked[name]
+	if !ok {
+		return slices.Clone(releases)
+	}
+	var res Releases[R, D]
+	for _, release := range releases {
+		if release.GetVersion().Equal(locked) {
+			res = append(res, release)
+		}
+	}
+	return res
+}
+
+// sortCandidates sorts the releases of the package in the order they should
+// be tried: the preferred version first, then by the CandidateOrder.
+func (r *resolution[R, D]) sortCandidates(name string, releases Releases[R, D]) {
+	if r.order != nil {
+		r.order(name, releases)
+	} else {
+		releases.SortDescent()
+	}
+	if preferred, ok := r.preferred[name]; ok {
+		sort.SliceStable(releases, func(i, j int) bool {
+			return releases[i].GetVersion().Equal(preferred) && !releases[j].GetVersion().Equal(preferred)
+		})
+	}
 }
 
 type dependencyHash string
 
+// hashDependency returns the canonical hash of the dependency, the equivalent
+// constraints have the same hash.
 func hashDependency[D Dependency](dep D) dependencyHash {
-	return dependencyHash(dep.GetName() + "/" + dep.GetConstraint().String())
+	return dependencyHash(dep.GetName() + "/" + Simplify(dep.GetConstraint()).String())
+}
+
+// requirementsOf returns the dependencies of the release as Requirements, path is the
+// chain of releases that led to the release.
+func requirementsOf[R Release[D], D Dependency](release R, path []R) []*Requirement[R, D] {
+	deps := release.GetDependencies()
+	if len(deps) == 0 {
+		return nil
+	}
+	path = append(slices.Clip(path), release)
+	reqs := make([]Requirement[R, D], len(deps))
+	res := make([]*Requirement[R, D], len(deps))
+	for i, dep := range deps {
+		reqs[i] = Requirement[R, D]{Dependency: dep, Path: path}
+		res[i] = &reqs[i]
+	}
+	return res
+}
+
+// dependenciesOf returns the Requirements of the release selected for req.
+// They are cached, so when the same release is tried again for req the
+// Requirements, and their dependency keys, are reused.
+func (r *resolution[R, D]) dependenciesOf(req *Requirement[R, D], release R) []*Requirement[R, D] {
+	key := dependenciesKey[R, D]{req: req, version: release.GetVersion()}
+	res, ok := r.dependencies[key]
+	if !ok {
+		res = requirementsOf(release, req.Path)
+		r.dependencies[key] = res
+	}
+	return res
+}
+
+// step checks if the resolution must be aborted, because the context is done
+// or because a limit has been exceeded, and counts the step performed.
+func (r *resolution[R, D]) step(depth int) error {
+	if err := r.ctx.Err(); err != nil {
+		return err
+	}
+	if r.maxSteps > 0 && r.steps >= r.maxSteps {
+		return ErrMaxStepsExceeded
+	}
+	if r.maxDepth > 0 && depth > r.maxDepth {
+		return ErrMaxDepthExceeded
+	}
+	r.steps++
+	return nil
+}
+
+// updateBest records that the current solution is the largest found so far,
+// the copy is taken later by saveBest
+func (r *resolution[R, D]) updateBest() {
+	if len(r.solution) <= r.bestSize {
+		return
+	}
+	r.bestSize = len(r.solution)
+	r.bestStale = true
+}
+
+// saveBest copies the current solution if it is the largest found so far, it
+// must be called before removing releases from the solution.
+func (r *resolution[R, D]) saveBest() {
+	if !r.bestStale || len(r.solution) != r.bestSize {
+		return
+	}
+	r.bestStale = false
+	r.best = Releases[R, D]{}
+	for _, v := range r.solution {
+		r.best = append(r.best, v)
+	}
+}
+
+func (r *resolution[R, D]) aborted(reason error) *AbortedError[R, D] {
+	r.saveBest()
+	return &AbortedError[R, D]{
+		Root:         r.root,
+		Requirements: r.requirements,
+		Reason:       reason,
+		Partial:      r.best,
+		Steps:        r.steps,
+	}
 }
 
-func (ar *Resolver[R, D]) resolve() Releases[R, D] {
-	debug("deps to process: %v", ar.depsToProcess)
-	if len(ar.depsToProcess) == 0 {
+// rootRequirement returns the top-level requirement that led to req
+func (r *resolution[R, D]) rootRequirement(req *Requirement[R, D]) *Requirement[R, D] {
+	if len(req.Path) <= r.rootPathLen {
+		return req
+	}
+	// The first release in the path after the root has been selected
+	// by a top-level requirement
+	if selectedBy := r.selectedBy[req.Path[r.rootPathLen].GetName()]; selectedBy != nil {
+		return selectedBy
+	}
+	return req
+}
+
+// addMissingRoot records the top-level requirement that led to req, whose
+// package does not exist
+func (r *resolution[R, D]) addMissingRoot(req *Requirement[R, D]) {
+	root := r.rootRequirement(req)
+	r.missingRoots[root.dependencyKey()] = root
+}
+
+func (r *resolution[R, D]) resolve() (Releases[R, D], error) {
+	r.depth++
+	defer func() { r.depth-- }()
+	if err := r.step(r.depth); err != nil {
+		return nil, err
+	}
+
+	debug("deps to process: %v", r.depsToProcess)
+	if len(r.depsToProcess) == 0 {
 		debug("All dependencies have been resolved.")
-		var res Releases[R, D]
-		for _, v := range ar.solution {
+		res := Releases[R, D]{}
+		for _, v := range r.solution {
 			res = append(res, v)
 		}
-		return res
+		return res, nil
 	}
 
 	// Pick the first dependency in the deps to process
-	dep := ar.depsToProcess[0]
+	req := r.depsToProcess[0]
+	dep := req.Dependency
 	depName := dep.GetName()
 	debug("Considering next dep: %s", depName)
 
 	// If a release is already picked in the solution check if it match the dep
-	if existingRelease, has := ar.solution[depName]; has {
-		if dep.GetConstraint().Match(existingRelease.GetVersion()) {
+	if existingRelease, has := r.solution[depName]; has {
+		if r.matchCtx.Match(dep.GetConstraint(), existingRelease.GetVersion()) {
 			debug("%v already in solution and matching", existingRelease)
-			oldDepsToProcess := ar.depsToProcess
-			ar.depsToProcess = ar.depsToProcess[1:]
-			if res := ar.resolve(); res != nil {
-				return res
+			oldDepsToProcess := r.depsToProcess
+			r.depsToProcess = r.depsToProcess[1:]
+			if res, err := r.resolve(); res != nil || err != nil {
+				return res, err
 			}
-			ar.depsToProcess = oldDepsToProcess
-			return nil
+			r.depsToProcess = oldDepsToProcess
+			return nil, nil
 		}
 		debug("%v already in solution do not match... rolling back", existingRelease)
-		return nil
+		r.addConflict(depName, req.dependencyKey(), r.selectedBy[depName], req)
+		return nil, nil
 	}
 
 	// Otherwise start backtracking the dependency
-	releases := ar.releases[depName].FilterBy(dep.GetConstraint())
+	available, err := r.releasesFor(depName)
+	if err != nil {
+		return nil, err
+	}
+	releases := r.selectable(depName, available).FilterByContext(dep.GetConstraint(), r.matchCtx)
+	if len(releases) == 0 {
+		r.addConflict(depName, req.dependencyKey(), nil, req)
+	}
 
-	// Consider the latest versions first
-	releases.SortDescent()
+	// Consider the preferred and the best versions first
+	r.sortCandidates(depName, releases)
 	debug("releases matching criteria: %v", releases)
 
 backtracking_loop:
@@ -147,29 +693,121 @@ backtracking_loop:
 		debug("try with %v %v", release, releaseDeps)
 
 		for _, releaseDep := range releaseDeps {
-			if _, ok := ar.releases[releaseDep.GetName()]; !ok {
+			depReleases, err := r.releasesFor(releaseDep.GetName())
+			if err != nil {
+				return nil, err
+			}
+			if len(depReleases) == 0 {
 				debug("%v did not work, because its dependency %s does not exist", release, releaseDep.GetName())
+				r.missing[releaseDep.GetName()] = true
+				r.addMissingRoot(req)
 				continue backtracking_loop
 			}
 		}
 
-		ar.solution[depName] = release
-		oldDepsToProcess := ar.depsToProcess
-		ar.depsToProcess = append(ar.depsToProcess[1:], releaseDeps...)
+		r.solution[depName] = release
+		r.selectedBy[depName] = req
+		r.updateBest()
+		oldDepsToProcess := r.depsToProcess
+		r.depsToProcess = append(r.depsToProcess[1:], r.dependenciesOf(req, release)...)
 		// bubble up problematics deps so they are processed first
-		sort.Slice(ar.depsToProcess, func(i, j int) bool {
-			ci := hashDependency(ar.depsToProcess[i])
-			cj := hashDependency(ar.depsToProcess[j])
-			return ar.problematicDeps[ci] > ar.problematicDeps[cj]
-		})
-		if res := ar.resolve(); res != nil {
-			return res
+		if len(r.problematicDeps) > 0 {
+			sort.Slice(r.depsToProcess, func(i, j int) bool {
+				ci := r.depsToProcess[i].dependencyKey()
+				cj := r.depsToProcess[j].dependencyKey()
+				return r.problematicDeps[ci] > r.problematicDeps[cj]
+			})
+		}
+		if res, err := r.resolve(); res != nil || err != nil {
+			return res, err
 		}
-		ar.depsToProcess = oldDepsToProcess
+		r.depsToProcess = oldDepsToProcess
 		debug("%v did not work...", release)
-		delete(ar.solution, depName)
+		r.saveBest()
+		delete(r.solution, depName)
+		delete(r.selectedBy, depName)
 	}
 
-	ar.problematicDeps[hashDependency(dep)]++
-	return nil
+	r.problematicDeps[req.dependencyKey()]++
+	return nil, nil
+}
+
+// addConflict records a conflict on the package pkg between req and the
+// requirement that selected the release of pkg (selectedBy, that may be nil
+// if no release of pkg satisfies req). trigger is the hash of the dependency
+// that triggered the conflict.
+func (r *resolution[R, D]) addConflict(pkg string, trigger dependencyHash, selectedBy, req *Requirement[R, D]) {
+	key := conflictKey{pkg: pkg, hash: r.canonicalKey(req)}
+	if selectedBy != nil {
+		key.selectedBy = r.canonicalKey(selectedBy)
+	}
+	if _, has := r.conflicts[key]; has {
+		return
+	}
+	reqs := []*Requirement[R, D]{req}
+	if selectedBy != nil {
+		reqs = []*Requirement[R, D]{selectedBy, req}
+	}
+	roots := make([]*Requirement[R, D], len(reqs))
+	for i, req := range reqs {
+		roots[i] = r.rootRequirement(req)
+	}
+	r.conflicts[key] = &conflictRecord[R, D]{
+		conflict: &Conflict[R, D]{Package: pkg, Requirements: reqs},
+		roots:    roots,
+		trigger:  trigger,
+		order:    len(r.conflicts),
+	}
+}
+
+// canonicalKey returns the canonical hash of the Dependency of req, used to
+// deduplicate the conflicts between equivalent requirements. The hashes are
+// cached because simplifying the constraints is expensive.
+func (r *resolution[R, D]) canonicalKey(req *Requirement[R, D]) dependencyHash {
+	key := req.dependencyKey()
+	res, ok := r.canonicalKeys[key]
+	if !ok {
+		res = hashDependency(req.Dependency)
+		r.canonicalKeys[key] = res
+	}
+	return res
+}
+
+// report builds a ResolutionError from the conflicts collected during the
+// last resolution, the most problematic conflicts are reported first.
+func (r *resolution[R, D]) report() *ResolutionError[R, D] {
+	records := make([]*conflictRecord[R, D], 0, len(r.conflicts))
+	for _, record := range r.conflicts {
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		pi := r.problematicDeps[records[i].trigger]
+		pj := r.problematicDeps[records[j].trigger]
+		if pi != pj {
+			return pi > pj
+		}
+		return records[i].order < records[j].order
+	})
+	res := &ResolutionError[R, D]{Root: r.root, Requirements: r.requirements}
+	failed := map[*Requirement[R, D]]bool{}
+	addFailed := func(req *Requirement[R, D]) {
+		if !failed[req] {
+			failed[req] = true
+			res.FailedRequirements = append(res.FailedRequirements, req)
+		}
+	}
+	for _, record := range records {
+		res.Conflicts = append(res.Conflicts, record.conflict)
+		for _, req := range record.roots {
+			addFailed(req)
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(r.missingRoots)) {
+		addFailed(r.missingRoots[key])
+	}
+	for pkg := range r.missing {
+		res.Missing = append(res.Missing, pkg)
+	}
+	sort.Strings(res.Missing)
+	return res
 }
@@ -0,0 +1,188 @@
+//
+// Copyright 2018-2025 Cristian Maglie. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+
+package semver
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrReleaseNotFound is returned when the release to resolve is not part of
+// the Resolver archive
+var ErrReleaseNotFound = errors.New("release not found")
+
+// ErrMaxStepsExceeded is the reason of an AbortedError when the resolution
+// exceeds the maximum number of steps set with Resolver.SetMaxSteps
+var ErrMaxStepsExceeded = errors.New("maximum number of resolution steps exceeded")
+
+// ErrMaxDepthExceeded is the reason of an AbortedError when the resolution
+// exceeds the maximum depth set with Resolver.SetMaxDepth
+var ErrMaxDepthExceeded = errors.New("maximum resolution depth exceeded")
+
+// ErrNotLowerBound is returned by the MinimalVersionSelection algorithm when
+// a constraint can not be used as a lower bound
+var ErrNotLowerBound = errors.New("constraint is not expressible as a lower bound")
+
+// Requirement is a Dependency together with the chain of releases that
+// led the resolver to consider it
+type Requirement[R Release[D], D Dependency] struct {
+	// Dependency is the required dependency
+	Dependency D
+	// Path is the chain of releases, starting from the root release, that
+	// led to the Dependency. The last element is the release that declares
+	// the Dependency.
+	Path []R
+
+	hash dependencyHash
+}
+
+// dependencyKey returns the key of the Dependency used during the search,
+// it is computed only once because it's needed at each step.
+func (r *Requirement[R, D]) dependencyKey() dependencyHash {
+	if r.hash == "" {
+		r.hash = dependencyHash(r.Dependency.GetName() + "/" + r.Dependency.GetConstraint().String())
+	}
+	return r.hash
+}
+
+func (r *Requirement[R, D]) String() string {
+	res := dependencyString(r.Dependency)
+	if len(r.Path) > 0 {
+		res += " (required by "
+		for i, rel := range r.Path {
+			if i > 0 {
+				res += " > "
+			}
+			res += releaseString(rel)
+		}
+		res += ")"
+	}
+	return res
+}
+
+// Conflict is a set of Requirements on the same package that could not be
+// satisfied together
+type Conflict[R Release[D], D Dependency] struct {
+	// Package is the name of the package subject of the conflict
+	Package string
+	// Requirements are the conflicting requirements. A single Requirement
+	// means that no release of the Package satisfies it.
+	Requirements []*Requirement[R, D]
+}
+
+func (c *Conflict[R, D]) String() string {
+	if len(c.Requirements) == 1 {
+		return "no release of " + c.Package + " satisfies " + c.Requirements[0].String()
+	}
+	res := "conflicting requirements on " + c.Package + ": "
+	for i, req := range c.Requirements {
+		if i > 0 {
+			res += " and "
+		}
+		res += req.String()
+	}
+	return res
+}
+
+// ResolutionError is returned when the Resolver can not find a solution
+type ResolutionError[R Release[D], D Dependency] struct {
+	// Root is the release that was being resolved, it is the zero value if
+	// the resolution was started with ResolveAll.
+	Root R
+	// Requirements are the top-level dependencies passed to ResolveAll
+	Requirements []D
+	// FailedRequirements are the top-level requirements involved in the
+	// failure: the dependencies of the Root release or the Requirements
+	// passed to ResolveAll.
+	FailedRequirements []*Requirement[R, D]
+	// Conflicts are the conflicts found during the resolution, the most
+	// problematic conflicts come first. It is filled only by the Backtracking
+	// algorithm.
+	Conflicts []*Conflict[R, D]
+	// Missing are the names of the packages required by some release but
+	// missing from the archive
+	Missing []string
+	// Explanation is a human readable derivation of the failure, one step
+	// per line. It is filled only by the PubGrub algorithm.
+	Explanation string
+}
+
+func (e *ResolutionError[R, D]) Error() string {
+	res := "dependency resolution failed for " + resolutionTarget(e.Root, e.Requirements)
+	if e.Explanation != "" {
+		return res + ":\n" + e.Explanation
+	}
+	var reasons []string
+	for _, c := range e.Conflicts {
+		reasons = append(reasons, c.String())
+	}
+	if len(e.Missing) > 0 {
+		reasons = append(reasons, "missing packages: "+strings.Join(e.Missing, ", "))
+	}
+	if len(reasons) > 0 {
+		res += ": " + strings.Join(reasons, "; ")
+	}
+	return res
+}
+
+// AbortedError is returned when the resolution is interrupted before reaching
+// a result, because the context is done, because a limit is exceeded or
+// because the ReleaseProvider failed
+type AbortedError[R Release[D], D Dependency] struct {
+	// Root is the release that was being resolved, it is the zero value if
+	// the resolution was started with ResolveAll.
+	Root R
+	// Requirements are the top-level dependencies passed to ResolveAll
+	Requirements []D
+	// Reason is the cause of the interruption: ErrMaxStepsExceeded,
+	// ErrMaxDepthExceeded, the error of the context or the error of the
+	// ReleaseProvider
+	Reason error
+	// Partial is the best-effort partial solution, the largest set of
+	// consistent releases found before the interruption
+	Partial Releases[R, D]
+	// Steps is the number of steps performed before the interruption
+	Steps int
+}
+
+func (e *AbortedError[R, D]) Error() string {
+	return fmt.Sprintf("dependency resolution aborted for %s after %d steps: %s", resolutionTarget(e.Root, e.Requirements), e.Steps, e.Reason)
+}
+
+// Unwrap returns the Reason of the interruption
+func (e *AbortedError[R, D]) Unwrap() error {
+	return e.Reason
+}
+
+type conflictRecord[R Release[D], D Dependency] struct {
+	conflict *Conflict[R, D]
+	roots    []*Requirement[R, D]
+	trigger  dependencyHash
+	order    int
+}
+
+// resolutionTarget returns a description of what was being resolved: the
+// root release or the top-level requirements.
+func resolutionTarget[R Release[D], D Dependency](root R, reqs []D) string {
+	if reqs == nil {
+		return releaseString(root)
+	}
+	var res []string
+	for _, req := range reqs {
+		res = append(res, dependencyString(req))
+	}
+	return strings.Join(res, ", ")
+}
+
+func releaseString[R Release[D], D Dependency](r R) string {
+	return r.GetName() + "@" + r.GetVersion().String()
+}
+
+func dependencyString[D Dependency](d D) string {
+	return d.GetName() + d.GetConstraint().String()
+}
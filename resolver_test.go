@@ -7,7 +7,12 @@
 package semver
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"sort"
+	"sync"
 	"testing"
 	"time"
 
@@ -79,6 +84,11 @@ func rel(name, ver string, deps []*customDep) *customRel {
 }
 
 func TestResolver(t *testing.T) {
+	t.Run("Backtracking", func(t *testing.T) { testResolver(t, Backtracking) })
+	t.Run("PubGrub", func(t *testing.T) { testResolver(t, PubGrub) })
+}
+
+func testResolver(t *testing.T, algorithm Algorithm) {
 	a100 := rel("A", "1.0.0", deps("B>=1.2.0", "C>=2.0.0"))
 	a110 := rel("A", "1.1.0", deps("B=1.2.0", "C>=2.0.0"))
 	a111 := rel("A", "1.1.1", deps("B", "C=1.1.1"))
@@ -124,6 +134,7 @@ func TestResolver(t *testing.T) {
 	i170 := rel("I", "1.7.0", deps())
 	i180 := rel("I", "1.8.0", deps())
 	arch := NewResolver[*customRel]()
+	arch.SetAlgorithm(algorithm)
 	arch.AddReleases(
 		a100, a110, a111, a120, a121,
 		b131, b130, b121, b120, b111, b110, b100,
@@ -187,3 +198,518 @@ func TestResolver(t *testing.T) {
 	r7 := arch.Resolve(e101)
 	require.Nil(t, r7)
 }
+
+func TestResolverReport(t *testing.T) {
+	a100 := rel("A", "1.0.0", deps("B>=1.2.0", "C>=2.0.0"))
+	a110 := rel("A", "1.1.0", deps("B", "C>2.0.0"))
+	a120 := rel("A", "1.2.0", deps("B", "E"))
+	b100 := rel("B", "1.0.0", deps("C<2.0.0"))
+	b120 := rel("B", "1.2.0", deps("C<2.0.0"))
+	c100 := rel("C", "1.0.0", deps())
+	c200 := rel("C", "2.0.0", deps())
+	e100 := rel("E", "1.0.0", deps("F"))
+	arch := NewResolver[*customRel]()
+	arch.AddReleases(a100, a110, a120, b100, b120, c100, c200, e100)
+
+	_, err := arch.ResolveWithReport(rel("A", "1.3.0", deps()))
+	require.ErrorIs(t, err, ErrReleaseNotFound)
+
+	res, err := arch.ResolveWithReport(a100)
+	require.Nil(t, res)
+	var resErr *ResolutionError[*customRel, *customDep]
+	require.ErrorAs(t, err, &resErr)
+	require.Equal(t, a100, resErr.Root)
+	require.Empty(t, resErr.Missing)
+	require.Len(t, resErr.Conflicts, 1)
+	conflict := resErr.Conflicts[0]
+	require.Equal(t, "C", conflict.Package)
+	require.Len(t, conflict.Requirements, 2)
+	require.Equal(t, "C>=2.0.0", dependencyString(conflict.Requirements[0].Dependency))
+	require.Equal(t, []*customRel{a100}, conflict.Requirements[0].Path)
+	require.Equal(t, "C<2.0.0", dependencyString(conflict.Requirements[1].Dependency))
+	require.Equal(t, []*customRel{a100, b120}, conflict.Requirements[1].Path)
+	require.Equal(t, "dependency resolution failed for A@1.0.0: "+
+		"conflicting requirements on C: C>=2.0.0 (required by A@1.0.0) and C<2.0.0 (required by A@1.0.0 > B@1.2.0)", err.Error())
+
+	_, err = arch.ResolveWithReport(a110)
+	require.ErrorAs(t, err, &resErr)
+	require.Len(t, resErr.Conflicts, 1)
+	require.Equal(t, "no release of C satisfies C>2.0.0 (required by A@1.1.0)", resErr.Conflicts[0].String())
+
+	_, err = arch.ResolveWithReport(a120)
+	require.ErrorAs(t, err, &resErr)
+	require.Equal(t, []string{"F"}, resErr.Missing)
+	require.Empty(t, resErr.Conflicts)
+	require.Equal(t, "dependency resolution failed for A@1.2.0: missing packages: F", err.Error())
+}
+
+func TestResolverMissingRoots(t *testing.T) {
+	a := rel("A", "1.0.0", deps("B", "C"))
+	arch := NewResolver[*customRel]()
+	arch.AddRelease(a)
+	for i := range 10 {
+		arch.AddRelease(rel("B", fmt.Sprintf("1.%d.0", i), deps("F")))
+		arch.AddRelease(rel("C", fmt.Sprintf("1.%d.0", i), deps("E")))
+	}
+	_, err := arch.ResolveWithReport(a)
+	var resErr *ResolutionError[*customRel, *customDep]
+	require.ErrorAs(t, err, &resErr)
+	require.Equal(t, []string{"F"}, resErr.Missing)
+	// The top-level requirement is reported once, even if all the releases
+	// of B have been tried
+	require.Len(t, resErr.FailedRequirements, 1)
+	require.Equal(t, "B", dependencyString(resErr.FailedRequirements[0].Dependency))
+}
+
+func TestResolverPubGrub(t *testing.T) {
+	a := rel("A", "1.0.0", deps("B^1.0.0", "C^1.0.0"))
+	arch := NewResolver[*customRel]()
+	arch.SetAlgorithm(PubGrub)
+	arch.AddReleases(a,
+		rel("B", "1.0.0", deps("D^1.0.0")),
+		rel("B", "1.1.0", deps("D^1.0.0")),
+		rel("C", "1.0.0", deps("D^2.0.0")),
+		rel("C", "1.1.0", deps("E")),
+		rel("D", "1.0.0", deps()),
+		rel("D", "2.0.0", deps()),
+	)
+	res, err := arch.ResolveWithReport(a)
+	require.Nil(t, res)
+	var resErr *ResolutionError[*customRel, *customDep]
+	require.ErrorAs(t, err, &resErr)
+	require.Equal(t, []string{"E"}, resErr.Missing)
+	require.Empty(t, resErr.Conflicts)
+	fmt.Println(err)
+	require.Equal(t, "dependency resolution failed for A@1.0.0:\n"+
+		"1. Because A@1.0.0 depends on B^1.0.0 and B depends on D^1.0.0, A@1.0.0 requires D^1.0.0.\n"+
+		"2. Because A@1.0.0 requires D^1.0.0 (1) and C<1.1.0 depends on D^2.0.0, A@1.0.0 is incompatible with C<1.1.0.\n"+
+		"3. Because A@1.0.0 is incompatible with C<1.1.0 (2) and A@1.0.0 depends on C^1.0.0, A@1.0.0 requires C^1.1.0.\n"+
+		"4. Because A@1.0.0 requires C^1.1.0 (3) and C>=1.1.0 depends on E, A@1.0.0 requires E.\n"+
+		"5. Because A@1.0.0 requires E (4) and E is missing from the archive, A@1.0.0 is forbidden.\n"+
+		"6. Because A@1.0.0 is forbidden (5) and A@1.0.0 is the release to resolve, version solving failed.",
+		err.Error())
+
+	// Many combinations of X, Y and W that can never satisfy the constraint on Z
+	root := rel("R", "1.0.0", deps("X", "Y", "W", "Z^2.0.0"))
+	arch = NewResolver[*customRel]()
+	arch.SetAlgorithm(PubGrub)
+	arch.AddReleases(root, rel("Z", "1.0.0", deps()), rel("Z", "2.0.0", deps()))
+	for i := range 50 {
+		arch.AddReleases(
+			rel("X", fmt.Sprintf("1.%d.0", i), deps(fmt.Sprintf("Y=1.%d.0", i))),
+			rel("Y", fmt.Sprintf("1.%d.0", i), deps(fmt.Sprintf("W=1.%d.0", i))),
+			rel("W", fmt.Sprintf("1.%d.0", i), deps("Z^1.0.0")),
+		)
+	}
+	done := make(chan bool)
+	go func() {
+		res, err := arch.ResolveWithReport(root)
+		require.Nil(t, res)
+		require.Error(t, err)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		require.FailNow(t, "test didn't complete in the allocated time")
+	}
+}
+
+func TestResolverLimits(t *testing.T) {
+	root := rel("R", "1.0.0", deps("X", "Y", "W", "Z^2.0.0"))
+	arch := NewResolver[*customRel]()
+	arch.AddReleases(root, rel("Z", "1.0.0", deps()), rel("Z", "2.0.0", deps()))
+	for i := range 20 {
+		arch.AddReleases(
+			rel("X", fmt.Sprintf("1.%d.0", i), deps(fmt.Sprintf("Y=1.%d.0", i))),
+			rel("Y", fmt.Sprintf("1.%d.0", i), deps(fmt.Sprintf("W=1.%d.0", i))),
+			rel("W", fmt.Sprintf("1.%d.0", i), deps("Z^1.0.0")),
+		)
+	}
+
+	for _, algorithm := range []Algorithm{Backtracking, PubGrub} {
+		arch.SetAlgorithm(algorithm)
+		var abortErr *AbortedError[*customRel, *customDep]
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		res, err := arch.ResolveContext(ctx, root)
+		require.Nil(t, res)
+		require.ErrorIs(t, err, context.Canceled)
+		require.ErrorAs(t, err, &abortErr)
+		require.Equal(t, root, abortErr.Root)
+
+		ctx, cancel = context.WithTimeout(context.Background(), time.Millisecond)
+		<-ctx.Done()
+		_, err = arch.ResolveContext(ctx, root)
+		cancel()
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		arch.SetMaxSteps(2)
+		_, err = arch.ResolveWithReport(root)
+		require.ErrorIs(t, err, ErrMaxStepsExceeded)
+		require.ErrorAs(t, err, &abortErr)
+		require.Equal(t, 2, abortErr.Steps)
+		require.Contains(t, abortErr.Partial, root)
+		require.Nil(t, arch.Resolve(root))
+		arch.SetMaxSteps(0)
+
+		arch.SetMaxDepth(2)
+		x130 := rel("X", "1.3.0", deps("Y=1.3.0"))
+		_, err = arch.ResolveWithReport(x130)
+		require.ErrorIs(t, err, ErrMaxDepthExceeded)
+		require.ErrorAs(t, err, &abortErr)
+		require.Contains(t, abortErr.Partial, x130)
+		fmt.Println(err)
+		arch.SetMaxDepth(0)
+
+		// Within the limits the resolution completes normally
+		arch.SetMaxSteps(100000)
+		arch.SetMaxDepth(100)
+		res, err = arch.ResolveContext(context.Background(), x130)
+		require.NoError(t, err)
+		require.Len(t, res, 4)
+		arch.SetMaxSteps(0)
+		arch.SetMaxDepth(0)
+	}
+
+	// The partial solution is the largest found, even if the resolver
+	// backtracked before being aborted
+	a200 := rel("A", "2.0.0", deps("B=1.0.0"))
+	b100 := rel("B", "1.0.0", deps("C>=2.0.0"))
+	arch = NewResolver[*customRel]()
+	root = rel("R", "1.0.0", deps("A>=1.0.0"))
+	arch.AddReleases(root, a200, b100, rel("C", "1.0.0", deps()), rel("A", "1.0.0", deps("C=1.0.0")))
+	arch.SetMaxSteps(4)
+	var abortErr *AbortedError[*customRel, *customDep]
+	_, err := arch.ResolveWithReport(root)
+	require.ErrorAs(t, err, &abortErr)
+	require.ElementsMatch(t, Releases[*customRel, *customDep]{root, a200, b100}, abortErr.Partial)
+}
+
+func TestResolverConcurrency(t *testing.T) {
+	a100 := rel("A", "1.0.0", deps("B^1.0.0", "C^1.0.0"))
+	arch := NewResolver[*customRel]()
+	arch.AddReleases(a100,
+		rel("B", "1.0.0", deps("C^1.0.0")),
+		rel("C", "1.0.0", deps()),
+	)
+
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			arch.AddRelease(rel("B", fmt.Sprintf("1.1.%d", i), deps("C^1.0.0")))
+		}()
+		go func() {
+			defer wg.Done()
+			for _, algorithm := range []Algorithm{Backtracking, PubGrub} {
+				arch.SetAlgorithm(algorithm)
+				res, err := arch.ResolveWithReport(a100)
+				require.NoError(t, err)
+				require.Len(t, res, 3)
+			}
+		}()
+	}
+	wg.Wait()
+	require.Len(t, arch.Resolve(a100), 3)
+}
+
+type customProvider struct {
+	mutex    sync.Mutex
+	releases map[string]Releases[*customRel, *customDep]
+	requests map[string]int
+	failOn   string
+}
+
+func (p *customProvider) ReleasesFor(name string) (Releases[*customRel, *customDep], error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.requests[name]++
+	if name == p.failOn {
+		return nil, errors.New("database unavailable")
+	}
+	return p.releases[name], nil
+}
+
+func TestResolverProvider(t *testing.T) {
+	a100 := rel("A", "1.0.0", deps("B^1.0.0", "C^1.0.0"))
+	b100 := rel("B", "1.0.0", deps("C^1.0.0"))
+	b110 := rel("B", "1.1.0", deps("C^2.0.0"))
+	c100 := rel("C", "1.0.0", deps())
+	c200 := rel("C", "2.0.0", deps())
+	provider := &customProvider{
+		releases: map[string]Releases[*customRel, *customDep]{
+			"A": {a100},
+			"B": {b100, b110},
+			"C": {c200},
+			"D": {rel("D", "1.0.0", deps())},
+		},
+		requests: map[string]int{},
+	}
+	arch := NewResolverWithProvider(provider)
+	// Releases added to the archive are merged with the provided ones
+	arch.AddRelease(c100)
+
+	for _, algorithm := range []Algorithm{Backtracking, PubGrub} {
+		arch.SetAlgorithm(algorithm)
+		clear(provider.requests)
+		res, err := arch.ResolveWithReport(a100)
+		require.NoError(t, err)
+		require.ElementsMatch(t, Releases[*customRel, *customDep]{a100, b100, c100}, res)
+		// Each package is requested once and only if needed
+		require.Equal(t, map[string]int{"A": 1, "B": 1, "C": 1}, provider.requests)
+
+		provider.failOn = "C"
+		res, err = arch.ResolveWithReport(a100)
+		require.Nil(t, res)
+		var abortErr *AbortedError[*customRel, *customDep]
+		require.ErrorAs(t, err, &abortErr)
+		require.EqualError(t, abortErr.Reason, "fetching releases of C: database unavailable")
+		provider.failOn = ""
+	}
+
+	releases, err := arch.ReleasesFor("C")
+	require.NoError(t, err)
+	require.Equal(t, Releases[*customRel, *customDep]{c100}, releases)
+}
+
+func TestResolveAll(t *testing.T) {
+	b100 := rel("B", "1.0.0", deps("C^1.0.0"))
+	b110 := rel("B", "1.1.0", deps("C^2.0.0"))
+	c100 := rel("C", "1.0.0", deps())
+	c200 := rel("C", "2.0.0", deps())
+	d100 := rel("D", "1.0.0", deps("E"))
+	arch := NewResolver[*customRel]()
+	arch.AddReleases(b100, b110, c100, c200, d100, rel("E", "1.0.0", deps()))
+	a100 := rel("A", "1.0.0", deps("D", "B>=1.1.0", "C^1.0.0"))
+	arch.AddRelease(a100)
+
+	failedRequirements := func(err error) []string {
+		var resErr *ResolutionError[*customRel, *customDep]
+		require.ErrorAs(t, err, &resErr)
+		var res []string
+		for _, req := range resErr.FailedRequirements {
+			res = append(res, req.String())
+		}
+		return res
+	}
+
+	for _, algorithm := range []Algorithm{Backtracking, PubGrub} {
+		arch.SetAlgorithm(algorithm)
+
+		res, err := arch.ResolveAll(d("B"), d("C^1.0.0"))
+		require.NoError(t, err)
+		require.ElementsMatch(t, Releases[*customRel, *customDep]{b100, c100}, res)
+
+		res, err = arch.ResolveAll(d("B"), d("D"))
+		require.NoError(t, err)
+		require.Len(t, res, 4)
+		require.Contains(t, res, b110)
+		require.Contains(t, res, c200)
+
+		res, err = arch.ResolveAll()
+		require.NoError(t, err)
+		require.Empty(t, res)
+
+		res, err = arch.ResolveAll(d("D"), d("B>=1.1.0"), d("C^1.0.0"))
+		require.Nil(t, res)
+		require.ErrorContains(t, err, "dependency resolution failed for D, B>=1.1.0, C^1.0.0")
+		require.ElementsMatch(t, []string{"B>=1.1.0", "C^1.0.0"}, failedRequirements(err))
+		fmt.Println(err)
+
+		res, err = arch.ResolveAll(d("D"), d("F"))
+		require.Nil(t, res)
+		require.Equal(t, []string{"F"}, failedRequirements(err))
+
+		// With a root release the failed requirements are its dependencies
+		_, err = arch.ResolveWithReport(a100)
+		require.ElementsMatch(t, []string{"B>=1.1.0 (required by A@1.0.0)", "C^1.0.0 (required by A@1.0.0)"}, failedRequirements(err))
+	}
+}
+
+func TestResolverLockedVersions(t *testing.T) {
+	a100 := rel("A", "1.0.0", deps("B^1.0.0", "C^1.0.0"))
+	b100 := rel("B", "1.0.0", deps("C^1.0.0"))
+	b110 := rel("B", "1.1.0", deps("C^1.0.0"))
+	b120 := rel("B", "1.2.0", deps("C^1.1.0"))
+	c100 := rel("C", "1.0.0", deps())
+	c110 := rel("C", "1.1.0", deps())
+	arch := NewResolver[*customRel]()
+	arch.AddReleases(a100, b100, b110, b120, c100, c110)
+
+	for _, algorithm := range []Algorithm{Backtracking, PubGrub} {
+		arch.SetAlgorithm(algorithm)
+		arch.SetLockedVersions(nil)
+		arch.SetPreferredVersions(nil)
+		arch.SetUpgradeOnly()
+
+		// By default the latest releases are selected
+		res, err := arch.ResolveWithReport(a100)
+		require.NoError(t, err)
+		require.ElementsMatch(t, Releases[*customRel, *customDep]{a100, b120, c110}, res)
+
+		// Locked versions are kept
+		arch.SetLockedVersions(map[string]*Version{"B": v("1.0.0"), "C": v("1.0.0")})
+		res, err = arch.ResolveWithReport(a100)
+		require.NoError(t, err)
+		require.ElementsMatch(t, Releases[*customRel, *customDep]{a100, b100, c100}, res)
+
+		// Only B is upgraded, C is kept unless impossible
+		arch.SetUpgradeOnly("B")
+		res, err = arch.ResolveWithReport(a100)
+		require.NoError(t, err)
+		require.ElementsMatch(t, Releases[*customRel, *customDep]{a100, b110, c100}, res)
+		res, err = arch.ResolveAll(d("B>=1.2.0"))
+		require.NoError(t, err)
+		require.ElementsMatch(t, Releases[*customRel, *customDep]{b120, c110}, res)
+
+		// If the locked versions are not consistent they are just preferred,
+		// at least one of them is kept
+		arch.SetUpgradeOnly()
+		arch.SetLockedVersions(map[string]*Version{"B": v("1.2.0"), "C": v("1.0.0")})
+		res, err = arch.ResolveWithReport(a100)
+		require.NoError(t, err)
+		require.Len(t, res, 3)
+		require.True(t, slices.Contains(res, b120) || slices.Contains(res, c100))
+
+		// Preferred versions are tried first
+		arch.SetLockedVersions(nil)
+		arch.SetPreferredVersions(map[string]*Version{"B": v("1.1.0")})
+		res, err = arch.ResolveWithReport(a100)
+		require.NoError(t, err)
+		require.ElementsMatch(t, Releases[*customRel, *customDep]{a100, b110, c110}, res)
+		res, err = arch.ResolveAll(d("B>=1.2.0"))
+		require.NoError(t, err)
+		require.ElementsMatch(t, Releases[*customRel, *customDep]{b120, c110}, res)
+	}
+}
+
+func TestResolverMVS(t *testing.T) {
+	a100 := rel("A", "1.0.0", deps("B>=1.1.0", "C>=1.0.0"))
+	a200 := rel("A", "2.0.0", deps("B^1.1.0", "D"))
+	a300 := rel("A", "3.0.0", deps("B<1.0.0 || >=1.2.0"))
+	a400 := rel("A", "4.0.0", deps("B", "F"))
+	b110 := rel("B", "1.1.0", deps("C>=1.2.0"))
+	b120 := rel("B", "1.2.0", deps("C>=1.3.0"))
+	c120 := rel("C", "1.2.0", deps())
+	c130 := rel("C", "1.3.0", deps())
+	arch := NewResolver[*customRel]()
+	arch.SetAlgorithm(MinimalVersionSelection)
+	arch.AddReleases(a100, a200, a300, a400, b110, b120, c120, c130,
+		rel("B", "1.0.0", deps()),
+		rel("B", "2.0.0", deps()),
+		rel("C", "1.0.0", deps()),
+		rel("C", "1.1.0", deps()),
+		rel("D", "1.0.0", deps("B>=2.0.0")),
+	)
+
+	res, err := arch.ResolveWithReport(a100)
+	require.NoError(t, err)
+	require.ElementsMatch(t, Releases[*customRel, *customDep]{a100, b110, c120}, res)
+
+	res, err = arch.ResolveAll(d("B>=1.2.0"), d("C"))
+	require.NoError(t, err)
+	require.ElementsMatch(t, Releases[*customRel, *customDep]{b120, c130}, res)
+
+	// The upper bounds are checked after the selection
+	_, err = arch.ResolveWithReport(a200)
+	var resErr *ResolutionError[*customRel, *customDep]
+	require.ErrorAs(t, err, &resErr)
+	require.Equal(t, "dependency resolution failed for A@2.0.0: "+
+		"conflicting requirements on B: B>=2.0.0 (required by A@2.0.0 > D@1.0.0) and B^1.1.0 (required by A@2.0.0)", err.Error())
+
+	_, err = arch.ResolveWithReport(a300)
+	require.ErrorIs(t, err, ErrNotLowerBound)
+	require.EqualError(t, err, "constraint is not expressible as a lower bound: B(<1.0.0 || >=1.2.0) (required by A@3.0.0)")
+
+	_, err = arch.ResolveWithReport(a400)
+	require.ErrorAs(t, err, &resErr)
+	require.Equal(t, []string{"F"}, resErr.Missing)
+}
+
+func TestResolverCandidateOrder(t *testing.T) {
+	a100 := rel("A", "1.0.0", deps("B^1.0.0", "C"))
+	b100 := rel("B", "1.0.0", deps("C^1.0.0"))
+	b110 := rel("B", "1.1.0", deps("C^1.0.0", "D"))
+	b120 := rel("B", "1.2.0-rc", deps())
+	c100 := rel("C", "1.0.0", deps())
+	c110 := rel("C", "1.1.0", deps())
+	d100 := rel("D", "1.0.0", deps())
+	arch := NewResolver[*customRel]()
+	arch.AddReleases(a100, b100, b110, b120, c100, c110, d100)
+
+	oldestFirst := func(name string, candidates Releases[*customRel, *customDep]) {
+		candidates.SortAscent()
+	}
+	stableFirst := func(name string, candidates Releases[*customRel, *customDep]) {
+		candidates.SortDescent()
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return !candidates[i].GetVersion().IsPrerelease() && candidates[j].GetVersion().IsPrerelease()
+		})
+	}
+	fewestDependencies := func(name string, candidates Releases[*customRel, *customDep]) {
+		candidates.SortDescent()
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return len(candidates[i].GetDependencies()) < len(candidates[j].GetDependencies())
+		})
+	}
+	var called []string
+	tracked := func(name string, candidates Releases[*customRel, *customDep]) {
+		called = append(called, name)
+		candidates.SortDescent()
+	}
+
+	for _, algorithm := range []Algorithm{Backtracking, PubGrub} {
+		arch.SetAlgorithm(algorithm)
+
+		arch.SetCandidateOrder(nil)
+		require.ElementsMatch(t, Releases[*customRel, *customDep]{a100, b120, c110}, arch.Resolve(a100))
+
+		arch.SetCandidateOrder(oldestFirst)
+		require.ElementsMatch(t, Releases[*customRel, *customDep]{a100, b100, c100}, arch.Resolve(a100))
+
+		arch.SetCandidateOrder(stableFirst)
+		require.ElementsMatch(t, Releases[*customRel, *customDep]{a100, b110, c110, d100}, arch.Resolve(a100))
+
+		arch.SetCandidateOrder(fewestDependencies)
+		require.ElementsMatch(t, Releases[*customRel, *customDep]{a100, b120, c110}, arch.Resolve(a100))
+		arch.SetCandidateOrder(stableFirst)
+		arch.SetPreferredVersions(map[string]*Version{"B": v("1.0.0")})
+		require.ElementsMatch(t, Releases[*customRel, *customDep]{a100, b100, c110}, arch.Resolve(a100))
+		arch.SetPreferredVersions(nil)
+
+		called = nil
+		arch.SetCandidateOrder(tracked)
+		require.ElementsMatch(t, Releases[*customRel, *customDep]{a100, b120, c110}, arch.Resolve(a100))
+		require.Contains(t, called, "B")
+		require.Contains(t, called, "C")
+	}
+}
+
+func TestResolverExcludePrereleases(t *testing.T) {
+	a100 := rel("A", "1.0.0", deps("B>=1.0.0", "C>=1.0.0-beta"))
+	b100 := rel("B", "1.0.0", deps())
+	b200beta := rel("B", "2.0.0-beta", deps())
+	c100rc := rel("C", "1.0.0-rc", deps())
+	c110rc := rel("C", "1.1.0-rc.1", deps())
+	arch := NewResolver[*customRel]()
+	arch.AddReleases(a100, b100, b200beta, c100rc, c110rc)
+
+	for _, algorithm := range []Algorithm{Backtracking, PubGrub} {
+		arch.SetAlgorithm(algorithm)
+		arch.SetMatchContext(nil)
+		require.ElementsMatch(t, Releases[*customRel, *customDep]{a100, b200beta, c110rc}, arch.Resolve(a100))
+
+		arch.SetMatchContext(&MatchContext{ExcludePrereleases: true})
+		require.ElementsMatch(t, Releases[*customRel, *customDep]{a100, b100, c100rc}, arch.Resolve(a100))
+		res, err := arch.ResolveAll(d("C>=1.1.0"))
+		require.Nil(t, res)
+		require.Error(t, err)
+	}
+
+	releases := Releases[*customRel, *customDep]{b100, b200beta, c100rc}
+	require.Equal(t, Releases[*customRel, *customDep]{b100, b200beta}, releases.FilterBy(d("B>=1.0.0").cond))
+	require.Equal(t, Releases[*customRel, *customDep]{b100}, releases.FilterByContext(d("B>=1.0.0").cond, &MatchContext{ExcludePrereleases: true}))
+}
@@ -0,0 +1,139 @@
+//
+// Copyright 2018-2025 Cristian Maglie. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+
+package semver
+
+import (
+	"slices"
+	"strings"
+)
+
+// Simplify returns the canonical minimal form of the Constraint: nested And
+// and Or are flattened, redundant bounds are removed, Not is pushed inward and
+// overlapping ranges are merged. Two equivalent constraints are simplified to
+// the same form, so the String() of the result may be used as a map key.
+//
+// Constraints not defined in this package are kept as they are, only the
+// And, Or and Not around them are simplified.
+func Simplify(c Constraint) Constraint {
+	if set, ok := constraintToVersionSet(c); ok {
+		return set.constraint()
+	}
+
+	switch c := c.(type) {
+	case *Not:
+		return simplifyNot(c.Operand)
+	case *And:
+		var operands []Constraint
+		for _, op := range c.Operands {
+			op = Simplify(op)
+			if and, ok := op.(*And); ok {
+				operands = append(operands, and.Operands...)
+			} else {
+				operands = append(operands, op)
+			}
+		}
+		return simplifyOperands(operands, false)
+	case *Or:
+		var operands []Constraint
+		for _, op := range c.Operands {
+			op = Simplify(op)
+			if or, ok := op.(*Or); ok {
+				operands = append(operands, or.Operands...)
+			} else {
+				operands = append(operands, op)
+			}
+		}
+		return simplifyOperands(operands, true)
+	}
+	return c
+}
+
+// simplifyNot returns the simplified negation of c
+func simplifyNot(c Constraint) Constraint {
+	switch c := c.(type) {
+	case *Not:
+		return Simplify(c.Operand)
+	case *And:
+		// !(a && b) = !a || !b
+		var operands []Constraint
+		for _, op := range c.Operands {
+			operands = append(operands, &Not{op})
+		}
+		return Simplify(&Or{operands})
+	case *Or:
+		// !(a || b) = !a && !b
+		var operands []Constraint
+		for _, op := range c.Operands {
+			operands = append(operands, &Not{op})
+		}
+		return Simplify(&And{operands})
+	}
+	return &Not{c}
+}
+
+// simplifyOperands merges all the operands that can be converted into a set
+// of versions and removes the duplicates. The operands are in Or if or is
+// true, otherwise they are in And.
+func simplifyOperands(operands []Constraint, or bool) Constraint {
+	set := fullVersionSet()
+	if or {
+		set = versionSet{}
+	}
+	var others []Constraint
+	seen := map[string]bool{}
+	for _, op := range operands {
+		if opSet, ok := constraintToVersionSet(op); ok {
+			if or {
+				set = set.union(opSet)
+			} else {
+				set = set.intersect(opSet)
+			}
+			continue
+		}
+		if !seen[op.String()] {
+			seen[op.String()] = true
+			others = append(others, op)
+		}
+	}
+	if or && set.isFull() || !or && set.isEmpty() {
+		// The result does not depend on the other operands
+		return set.constraint()
+	}
+	slices.SortFunc(others, func(a, b Constraint) int {
+		return strings.Compare(a.String(), b.String())
+	})
+
+	var res []Constraint
+	if or && !set.isEmpty() || !or && !set.isFull() {
+		merged := set.constraint()
+		if or {
+			if o, ok := merged.(*Or); ok {
+				res = append(res, o.Operands...)
+			} else {
+				res = append(res, merged)
+			}
+		} else {
+			if a, ok := merged.(*And); ok {
+				res = append(res, a.Operands...)
+			} else {
+				res = append(res, merged)
+			}
+		}
+	}
+	res = append(res, others...)
+	switch {
+	case len(res) == 1:
+		return res[0]
+	case len(res) == 0 && or:
+		return &LessThan{minVersion}
+	case len(res) == 0:
+		return &True{}
+	case or:
+		return &Or{res}
+	}
+	return &And{res}
+}
@@ -0,0 +1,80 @@
+//
+// Copyright 2018-2025 Cristian Maglie. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+
+package semver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimplify(t *testing.T) {
+	tests := []struct {
+		in       string
+		expected string
+	}{
+		{"", ""},
+		{"=1.0", "=1.0.0"},
+		{"(>=1.0.0 && >=1.2.0) && !(<1.5.0)", ">=1.5.0"},
+		{">=1.0.0 && (>=1.2.0 && <2.0.0-0)", "^1.2.0"},
+		{">=1.0.0 && <1.0.0", "<0.0.0-0"},
+		{"<1.0.0 || >=1.0.0", ""},
+		{"(>=1.0.0 && <1.5.0) || (>=1.2.0 && <2.0.0)", "(>=1.0.0 && <2.0.0)"},
+		{"!(>=1.0.0 && <2.0.0)", "(<1.0.0 || >=2.0.0)"},
+		{"!!=1.2.3", "=1.2.3"},
+		{"!(=1.2.3)", "!(=1.2.3)"},
+		{"1.2.x || ~1.2.5", "~1.2.0"},
+		{"1.0.0 - 2.0 || >2.1.0", "((>=1.0.0 && <2.1.0-0) || >2.1.0)"},
+	}
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			c, err := ParseConstraint(test.in)
+			require.NoError(t, err)
+			s := Simplify(c)
+			require.Equal(t, test.expected, s.String())
+
+			// The simplified constraint can be parsed back
+			parsed, err := ParseConstraint(s.String())
+			require.NoError(t, err)
+			require.Equal(t, s.String(), parsed.String())
+
+			// The simplified constraint must be equivalent
+			for _, ver := range versionSetTestVersions {
+				require.Equal(t, c.Match(v(ver)), s.Match(v(ver)), ver)
+			}
+		})
+	}
+
+	// Equivalent constraints have the same form
+	a, err := ParseConstraint("^1.2 && !(<1.5.0)")
+	require.NoError(t, err)
+	b, err := ParseConstraint(">=1.5.0 && <2.0.0-0")
+	require.NoError(t, err)
+	require.Equal(t, Simplify(a).String(), Simplify(b).String())
+
+	// Unknown constraints are kept
+	custom := &customConstraint{}
+	require.Equal(t, "prerelease", Simplify(custom).String())
+	require.Equal(t, "(>=1.0.0 && prerelease)", Simplify(&And{[]Constraint{
+		&GreaterThanOrEqual{v("1.0.0")},
+		&And{[]Constraint{custom, &GreaterThan{v("0.5.0")}}},
+		custom,
+	}}).String())
+	require.Equal(t, "(<1.0.0 || !(prerelease))", Simplify(&Not{&And{[]Constraint{
+		&GreaterThanOrEqual{v("1.0.0")},
+		custom,
+	}}}).String())
+	require.Equal(t, "", Simplify(&Or{[]Constraint{custom, &True{}}}).String())
+	require.Equal(t, "<0.0.0-0", Simplify(&And{[]Constraint{custom, &Not{&True{}}}}).String())
+	require.Equal(t, "<0.0.0-0", Simplify(&Or{[]Constraint{&Not{&True{}}, &LessThan{v("0.0.0-0")}}}).String())
+}
+
+func TestHashDependency(t *testing.T) {
+	require.Equal(t, hashDependency(d("A>=1.0.0 && >=1.2.0")), hashDependency(d("A!(<1.2.0)")))
+	require.NotEqual(t, hashDependency(d("A>=1.0.0")), hashDependency(d("B>=1.0.0")))
+	require.NotEqual(t, hashDependency(d("A>=1.0.0")), hashDependency(d("A>1.0.0")))
+}
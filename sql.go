@@ -18,17 +18,17 @@ func (v *Version) Scan(value interface{}) error {
 		return fmt.Errorf("incompatible type %T for Version", value)
 	}
 
-	v.raw = raw
-	v.bytes = []byte(v.raw)
-	if err := parse(v); err != nil {
+	parsed, err := Parse(raw)
+	if err != nil {
 		return err
 	}
+	*v = *parsed
 	return nil
 }
 
 // Value implements the driver.Valuer interface
 func (v *Version) Value() (driver.Value, error) {
-	return v.raw, nil
+	return v.encodedString()
 }
 
 // Scan implements the sql.Scanner interface
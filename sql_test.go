@@ -62,3 +62,26 @@ func TestSQLDriverInterfaces(t *testing.T) {
 		require.Equal(t, "a1-2.2-3.3", rd2)
 	})
 }
+
+func TestSQLVersionWithOptions(t *testing.T) {
+	v, err := ParseWithOptions("V01.02.3-rc+build", ParseOptions{AllowPrefix: true, AllowLeadingZeros: true, AllowFourComponents: true})
+	require.NoError(t, err)
+	d, err := v.Value()
+	require.NoError(t, err)
+	require.Equal(t, "1.2.3-rc+build", d)
+	var u Version
+	require.NoError(t, u.Scan(d))
+	require.Equal(t, "1.2.3-rc+build", u.String())
+	require.Zero(t, v.CompareTo(&u))
+
+	// The fourth number can't be encoded
+	v, err = ParseWithOptions("1.2.3.4", ParseOptions{AllowPrefix: true, AllowLeadingZeros: true, AllowFourComponents: true})
+	require.NoError(t, err)
+	_, err = v.Value()
+	require.Error(t, err)
+
+	// The decoder is strict
+	for _, in := range []string{"v1.2.3", "01.02.03", "1.2.3.4", "V0001.2"} {
+		require.Error(t, u.Scan(in), in)
+	}
+}
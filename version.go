@@ -15,12 +15,25 @@ type Version struct {
 	patch      int
 	prerelease int
 	build      int
+
+	// spelling is the original string, when it differs from raw (see
+	// ParseWithOptions)
+	spelling string
+	// revision is the fourth number of the version, when it is greater than
+	// zero (see ParseOptions.AllowFourComponents)
+	revision string
 }
 
 func (v *Version) String() string {
 	if v == nil {
 		return ""
 	}
+	if v.spelling != "" {
+		return v.spelling
+	}
+	if v.revision != "" {
+		return v.raw[:v.patch] + "." + v.revision + v.raw[v.patch:]
+	}
 	return v.raw
 }
 
@@ -35,6 +48,10 @@ func (v *Version) NormalizedString() NormalizedString {
 	if v == nil {
 		return ""
 	}
+	if v.revision != "" {
+		major, minor, patch, _ := versionParts(v)
+		return NormalizedString(major + "." + minor + "." + patch + "." + v.revision + v.raw[v.patch:])
+	}
 	if v.major == 0 {
 		return NormalizedString("0.0.0")
 	} else if v.minor == v.major {
@@ -70,6 +87,7 @@ func (v *Version) Normalize() {
 		v.build += 2
 	}
 	v.bytes = []byte(v.raw)
+	v.spelling = ""
 }
 
 func compareNumber(a, b []byte) int {
@@ -202,6 +220,13 @@ func (v *Version) CompareTo(u *Version) int {
 		}
 	}
 
+	// The fourth number, if any, is compared after the patch
+	if v.revision != "" || u.revision != "" {
+		if res := compareNumber([]byte(v.revision), []byte(u.revision)); res != 0 {
+			return res
+		}
+	}
+
 	// if both versions have no pre-release, they are equal
 	if v.prerelease == vPatch && u.prerelease == uPatch {
 		return 0
@@ -456,6 +481,11 @@ func (v *Version) SortableString() string {
 	}
 
 	res := ";" + encodeNumber(vMajor) + "." + encodeNumber(vMinor) + "." + encodeNumber(vPatch)
+	if v.revision != "" {
+		// The "<" separator sorts after ";" and "-" so a version with a fourth
+		// number is greater than the same version without it
+		res += "<" + encodeNumber([]byte(v.revision))
+	}
 	// If there is no pre-release, add a ";" to the end, otherwise add a "-" followed by the pre-release.
 	// This ensure the correct ordering of the pre-release versions (that are always lower than the normal versions).
 	if v.prerelease == v.patch {
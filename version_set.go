@@ -0,0 +1,475 @@
+//
+// Copyright 2018-2025 Cristian Maglie. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+
+package semver
+
+// versionInterval is the half-open interval of versions [lower, upper).
+// A nil lower bound means that the interval is unbounded below, a nil upper
+// bound means that the interval is unbounded above.
+//
+// Any interval may be expressed in this form because each version has an
+// immediate successor (see successor), for example the closed interval
+// [1.0.0, 2.0.0] is equivalent to [1.0.0, 2.0.1-0).
+type versionInterval struct {
+	lower *Version
+	upper *Version
+}
+
+// versionSet is a set of versions represented as an ordered list of
+// disjoint and non-adjacent intervals. Since the representation is
+// canonical two sets are equal if they have the same intervals.
+type versionSet []versionInterval
+
+// minVersion is the lowest possible version "0.0.0-0" (it is built by hand
+// because the parser lookup tables are not yet initialized at this point)
+var minVersion = &Version{
+	raw:        "0.0.0-0",
+	bytes:      []byte("0.0.0-0"),
+	major:      1,
+	minor:      3,
+	patch:      5,
+	prerelease: 7,
+	build:      7,
+}
+
+func fullVersionSet() versionSet {
+	return versionSet{{}}
+}
+
+func versionRange(lower, upper *Version) versionSet {
+	if lower != nil && lower.Equal(minVersion) {
+		lower = nil
+	}
+	if lower != nil && upper != nil && !lower.LessThan(upper) {
+		return versionSet{}
+	}
+	if upper != nil && !minVersion.LessThan(upper) {
+		return versionSet{}
+	}
+	return versionSet{{lower: lower, upper: upper}}
+}
+
+func versionPoint(v *Version) versionSet {
+	v = versionBound(v)
+	return versionRange(v, successor(v))
+}
+
+// lowerLess returns true if the lower bound a is less than the lower bound b
+func lowerLess(a, b *Version) bool {
+	if a == nil {
+		return b != nil
+	}
+	return b != nil && a.LessThan(b)
+}
+
+// upperLess returns true if the upper bound a is less than the upper bound b
+func upperLess(a, b *Version) bool {
+	if a == nil {
+		return false
+	}
+	return b == nil || a.LessThan(b)
+}
+
+// upperBefore returns true if the upper bound a comes before (or touches)
+// the lower bound b.
+func upperBefore(a, b *Version) bool {
+	if a == nil || b == nil {
+		return false
+	}
+	return a.LessThanOrEqual(b)
+}
+
+// upperLessThanLower returns true if the upper bound a comes strictly before
+// the lower bound b, in other words if there is a gap between them.
+func upperLessThanLower(a, b *Version) bool {
+	if a == nil || b == nil {
+		return false
+	}
+	return a.LessThan(b)
+}
+
+func boundEqual(a, b *Version) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(b)
+}
+
+func (s versionSet) isEmpty() bool {
+	return len(s) == 0
+}
+
+func (s versionSet) isFull() bool {
+	return len(s) == 1 && s[0].lower == nil && s[0].upper == nil
+}
+
+func (s versionSet) contains(v *Version) bool {
+	for _, i := range s {
+		if i.lower != nil && v.LessThan(i.lower) {
+			return false
+		}
+		if i.upper == nil || v.LessThan(i.upper) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s versionSet) equal(t versionSet) bool {
+	if len(s) != len(t) {
+		return false
+	}
+	for i := range s {
+		if !boundEqual(s[i].lower, t[i].lower) || !boundEqual(s[i].upper, t[i].upper) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s versionSet) intersect(t versionSet) versionSet {
+	res := versionSet{}
+	i, j := 0, 0
+	for i < len(s) && j < len(t) {
+		a, b := s[i], t[j]
+		lower := a.lower
+		if lowerLess(lower, b.lower) {
+			lower = b.lower
+		}
+		upper := a.upper
+		if upperLess(b.upper, upper) {
+			upper = b.upper
+		}
+		if !upperBefore(upper, lower) {
+			res = append(res, versionInterval{lower: lower, upper: upper})
+		}
+		if upperLess(a.upper, b.upper) {
+			i++
+		} else {
+			j++
+		}
+	}
+	return res
+}
+
+func (s versionSet) union(t versionSet) versionSet {
+	all := make(versionSet, 0, len(s)+len(t))
+	i, j := 0, 0
+	for i < len(s) || j < len(t) {
+		if j == len(t) || (i < len(s) && lowerLess(s[i].lower, t[j].lower)) {
+			all = append(all, s[i])
+			i++
+		} else {
+			all = append(all, t[j])
+			j++
+		}
+	}
+	res := versionSet{}
+	for _, in := range all {
+		if n := len(res); n > 0 && !upperLessThanLower(res[n-1].upper, in.lower) {
+			// overlapping or adjacent intervals are merged
+			if upperLess(res[n-1].upper, in.upper) {
+				res[n-1].upper = in.upper
+			}
+			continue
+		}
+		res = append(res, in)
+	}
+	return res
+}
+
+func (s versionSet) complement() versionSet {
+	if len(s) == 0 {
+		return fullVersionSet()
+	}
+	res := versionSet{}
+	if s[0].lower != nil {
+		res = append(res, versionInterval{upper: s[0].lower})
+	}
+	for i, in := range s {
+		if in.upper == nil {
+			break
+		}
+		next := versionInterval{lower: in.upper}
+		if i+1 < len(s) {
+			next.upper = s[i+1].lower
+		}
+		res = append(res, next)
+	}
+	return res
+}
+
+func (s versionSet) difference(t versionSet) versionSet {
+	return s.intersect(t.complement())
+}
+
+// constraintToVersionSet converts the Constraint into the set of versions
+// matching it. Returns false if the Constraint is not supported.
+func constraintToVersionSet(c Constraint) (versionSet, bool) {
+	switch c := c.(type) {
+	case *True:
+		return fullVersionSet(), true
+	case *Equals:
+		return versionPoint(c.Version), true
+	case *LessThan:
+		return versionRange(nil, versionBound(c.Version)), true
+	case *LessThanOrEqual:
+		return versionRange(nil, successor(c.Version)), true
+	case *GreaterThan:
+		return versionRange(successor(c.Version), nil), true
+	case *GreaterThanOrEqual:
+		return versionRange(versionBound(c.Version), nil), true
+	case *CompatibleWith:
+		return versionRange(versionBound(c.Version), caretUpperBound(c.Version)), true
+	case *Tilde:
+		return versionRange(versionBound(c.Version), tildeUpperBound(c.Version)), true
+	case *XRange:
+		return constraintToVersionSet(c.Range())
+	case *HyphenRange:
+		return versionRange(versionBound(c.Lower), hyphenUpperBound(c.Upper)), true
+	case *Not:
+		set, ok := constraintToVersionSet(c.Operand)
+		if !ok {
+			return nil, false
+		}
+		return set.complement(), true
+	case *And:
+		res := fullVersionSet()
+		for _, op := range c.Operands {
+			set, ok := constraintToVersionSet(op)
+			if !ok {
+				return nil, false
+			}
+			res = res.intersect(set)
+		}
+		return res, true
+	case *Or:
+		res := versionSet{}
+		for _, op := range c.Operands {
+			set, ok := constraintToVersionSet(op)
+			if !ok {
+				return nil, false
+			}
+			res = res.union(set)
+		}
+		return res, true
+	}
+	return nil, false
+}
+
+// constraint converts the set back into an equivalent Constraint, the empty
+// set is "<0.0.0-0" that matches no version
+func (s versionSet) constraint() Constraint {
+	if len(s) == 0 {
+		return &LessThan{minVersion}
+	}
+	if len(s) == 2 && s[0].lower == nil && s[1].upper == nil {
+		// Check for the "not equals" special case
+		if p := s[0].upper; s[1].lower.Equal(successor(p)) {
+			return &Not{&Equals{p}}
+		}
+	}
+	var res []Constraint
+	for _, in := range s {
+		res = append(res, in.constraint())
+	}
+	if len(res) == 1 {
+		return res[0]
+	}
+	return &Or{res}
+}
+
+func (in versionInterval) constraint() Constraint {
+	lower, upper := in.lower, in.upper
+	if lower == nil && upper == nil {
+		return &True{}
+	}
+	var lowerConstraint, upperConstraint Constraint
+	if lower != nil {
+		if upper != nil && upper.Equal(successor(lower)) {
+			return &Equals{lower}
+		}
+		if p := predecessor(lower); p != nil {
+			lowerConstraint = &GreaterThan{p}
+		} else if upper != nil && upper.Equal(caretUpperBound(lower)) {
+			return &CompatibleWith{lower}
+		} else if upper != nil && upper.Equal(tildeUpperBound(lower)) {
+			return &Tilde{lower}
+		} else {
+			lowerConstraint = &GreaterThanOrEqual{lower}
+		}
+	}
+	if upper != nil {
+		if p := predecessor(upper); p != nil {
+			upperConstraint = &LessThanOrEqual{p}
+		} else {
+			upperConstraint = &LessThan{upper}
+		}
+	}
+	if lowerConstraint == nil {
+		return upperConstraint
+	}
+	if upperConstraint == nil {
+		return lowerConstraint
+	}
+	return &And{[]Constraint{lowerConstraint, upperConstraint}}
+}
+
+func (s versionSet) String() string {
+	return s.constraint().String()
+}
+
+// versionParts returns the major, minor and patch numbers of the version and
+// the pre-release part.
+func versionParts(v *Version) (major, minor, patch, prerelease string) {
+	major, minor, patch = "0", "0", "0"
+	if v.major > 0 {
+		major = v.raw[:v.major]
+	}
+	if v.minor > v.major {
+		minor = v.raw[v.major+1 : v.minor]
+	}
+	if v.patch > v.minor {
+		patch = v.raw[v.minor+1 : v.patch]
+	}
+	return major, minor, patch, v.Prerelease()
+}
+
+// versionComponents returns the number of numeric components specified in v
+func versionComponents(v *Version) int {
+	if v.minor == v.major {
+		return 1
+	}
+	if v.patch == v.minor {
+		return 2
+	}
+	return 3
+}
+
+func buildVersion(major, minor, patch, prerelease string) *Version {
+	res := major + "." + minor + "." + patch
+	if prerelease != "" {
+		res += "-" + prerelease
+	}
+	return MustParse(res)
+}
+
+// buildRevisionVersion is like buildVersion but adds the fourth number (the
+// revision, see ParseOptions.AllowFourComponents) if it is not zero
+func buildRevisionVersion(major, minor, patch, revision, prerelease string) *Version {
+	if revision == "0" {
+		return buildVersion(major, minor, patch, prerelease)
+	}
+	res := major + "." + minor + "." + patch + "." + revision
+	if prerelease != "" {
+		res += "-" + prerelease
+	}
+	v, err := ParseWithOptions(res, ParseOptions{AllowFourComponents: true})
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// versionRevision returns the fourth number of the version, "0" if missing
+func versionRevision(v *Version) string {
+	if v.revision == "" {
+		return "0"
+	}
+	return v.revision
+}
+
+// versionBound returns the normalized version without build metadata
+func versionBound(v *Version) *Version {
+	major, minor, patch, prerelease := versionParts(v)
+	return buildRevisionVersion(major, minor, patch, versionRevision(v), prerelease)
+}
+
+// successor returns the lowest version greater than v
+func successor(v *Version) *Version {
+	major, minor, patch, prerelease := versionParts(v)
+	revision := versionRevision(v)
+	if prerelease != "" {
+		// The lowest pre-release after 1.0.0-rc is 1.0.0-rc.0
+		return buildRevisionVersion(major, minor, patch, revision, prerelease+".0")
+	}
+	// The lowest version after 1.0.0 is 1.0.0.1-0, because the versions with
+	// a fourth number are ordered between 1.0.0 and 1.0.1-0
+	return buildRevisionVersion(major, minor, patch, incNumber(revision), "0")
+}
+
+// predecessor returns the greatest version less than v, if it exists,
+// otherwise it returns nil. Only the predecessors of the versions in the
+// form X.Y.Z.R-0 (with R > 0) are computed.
+func predecessor(v *Version) *Version {
+	major, minor, patch, prerelease := versionParts(v)
+	revision := versionRevision(v)
+	if prerelease != "0" || revision == "0" {
+		return nil
+	}
+	return buildRevisionVersion(major, minor, patch, decNumber(revision), "")
+}
+
+// caretUpperBound returns the lowest version not compatible with v
+func caretUpperBound(v *Version) *Version {
+	major, minor, patch, _ := versionParts(v)
+	if major != "0" {
+		return buildVersion(incNumber(major), "0", "0", "0")
+	}
+	if minor != "0" {
+		return buildVersion("0", incNumber(minor), "0", "0")
+	}
+	return buildVersion("0", "0", incNumber(patch), "0")
+}
+
+// tildeUpperBound returns the lowest version not matched by ~v
+func tildeUpperBound(v *Version) *Version {
+	major, minor, _, _ := versionParts(v)
+	if v.minor == v.major {
+		// Only the major version is specified
+		return buildVersion(incNumber(major), "0", "0", "0")
+	}
+	return buildVersion(major, incNumber(minor), "0", "0")
+}
+
+// hyphenUpperBound returns the lowest version not included in an hyphen range
+// with the upper version v
+func hyphenUpperBound(v *Version) *Version {
+	if v.patch == v.minor && !v.IsPrerelease() {
+		// The patch number is not specified
+		return tildeUpperBound(v)
+	}
+	return successor(v)
+}
+
+// incNumber increments by one the decimal number n
+func incNumber(n string) string {
+	res := []byte(n)
+	for i := len(res) - 1; i >= 0; i-- {
+		if res[i] != '9' {
+			res[i]++
+			return string(res)
+		}
+		res[i] = '0'
+	}
+	return "1" + string(res)
+}
+
+// decNumber decrements by one the decimal number n, n must be greater than 0
+func decNumber(n string) string {
+	res := []byte(n)
+	for i := len(res) - 1; i >= 0; i-- {
+		if res[i] != '0' {
+			res[i]--
+			break
+		}
+		res[i] = '9'
+	}
+	if len(res) > 1 && res[0] == '0' {
+		res = res[1:]
+	}
+	return string(res)
+}
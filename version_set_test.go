@@ -0,0 +1,128 @@
+//
+// Copyright 2018-2025 Cristian Maglie. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+//
+
+package semver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var versionSetTestVersions = []string{
+	"0.0.0-0", "0.0.0", "0.0.1-rc", "0.0.1", "0.1.0", "0.1.1", "0.2.0-0", "0.2.0",
+	"1.0.0-0", "1.0.0-rc", "1.0.0-rc.0", "1.0.0-rc.1", "1.0.0", "1.0.1-0", "1.0.1",
+	"1.1.0", "1.2.3-beta", "1.2.3", "1.2.4", "1.3.0-0", "1.3.0", "1.9.9", "2.0.0-0",
+	"2.0.0-rc", "2.0.0", "2.0.1", "2.1.0", "3.0.0", "10.0.0",
+}
+
+func TestVersionSetFromConstraint(t *testing.T) {
+	constraints := []string{
+		"", "=1.0.0", "<1.0.0", "<=1.0.0", ">1.0.0", ">=1.0.0",
+		"^1.2.3", "^0.1.0", "^0.0.1", "^1.0.0-rc", ">1.0.0-rc", "<=1.0.0-rc",
+		"~1.2.3", "~1.2", "~1", "~0.1.1", "~1.0.0-rc", "1.2.x", "1.x", "*", "0.x",
+		"1.0.0 - 1.2.3", "0.1 - 1", "1.0.0-rc - 2.0.0-rc", "1.0.0 - 1.0.0",
+		"!(=1.0.0)", ">1.0.0 && <1.0.1-0", ">=1.0.0 || <0.1.0", "!(>=1.0.0 && <2.0.0)",
+		"(>=0.1.0 && <1.0.0) || (>=1.2.3 && <2.0.0) || =2.1.0", "=1.0.0 || =1.0.1 || =1.1.0",
+		"<1.0.0 || >=1.0.0", ">2.0.0 && <1.0.0", "<0.0.0-0", ">=0.0.0-0",
+	}
+	for _, in := range constraints {
+		c, err := ParseConstraint(in)
+		require.NoError(t, err)
+		set, ok := constraintToVersionSet(c)
+		require.True(t, ok)
+		back := set.constraint()
+		for _, s := range versionSetTestVersions {
+			v := MustParse(s)
+			require.Equal(t, c.Match(v), set.contains(v), "matching %s against %s", s, in)
+			require.Equal(t, c.Match(v), back.Match(v), "matching %s against %s (converted back to %s)", s, in, back)
+		}
+	}
+
+	_, ok := constraintToVersionSet(&Not{&customConstraint{}})
+	require.False(t, ok)
+}
+
+type customConstraint struct{}
+
+func (c *customConstraint) Match(v *Version) bool { return v.IsPrerelease() }
+func (c *customConstraint) String() string        { return "prerelease" }
+
+func TestVersionSetOperations(t *testing.T) {
+	set := func(in string) versionSet {
+		c, err := ParseConstraint(in)
+		require.NoError(t, err)
+		res, ok := constraintToVersionSet(c)
+		require.True(t, ok)
+		return res
+	}
+	require.True(t, set("").isFull())
+	require.True(t, set(">=0.0.0-0").isFull())
+	require.True(t, set("<1.0.0 || >=1.0.0").isFull())
+	require.True(t, set(">2.0.0 && <1.0.0").isEmpty())
+	require.True(t, set(">1.0.0 && <=1.0.0").isEmpty())
+	require.True(t, set("<0.0.0-0").isEmpty())
+	require.False(t, set(">1.0.0").equal(set(">=1.0.1-0")))
+	require.True(t, set(">1.0.0").complement().equal(set("<=1.0.0")))
+	require.True(t, set("^1.2.3").equal(set(">=1.2.3 && <2.0.0-0")))
+	require.True(t, set("!(<1.0.0 || >=2.0.0)").equal(set(">=1.0.0 && <2.0.0")))
+
+	require.True(t, set("^1.0.0").intersect(set("^1.5.0")).equal(set("^1.5.0")))
+	require.True(t, set("^1.0.0").union(set("^1.5.0")).equal(set("^1.0.0")))
+	require.True(t, set("<1.0.0").union(set(">=1.0.0 && <2.0.0")).equal(set("<2.0.0")))
+	require.True(t, set("<1.0.0").union(set("<2.0.0")).equal(set("<2.0.0")))
+	require.True(t, set(">=3.0.0").union(set("<1.0.0")).equal(set("<1.0.0 || >=3.0.0")))
+	require.True(t, set("^1.0.0").difference(set("=1.2.0")).equal(set(">=1.0.0 && <1.2.0 || >1.2.0 && <2.0.0-0")))
+	require.True(t, set("").complement().isEmpty())
+	require.True(t, versionSet{}.complement().isFull())
+
+	require.Equal(t, "^1.2.3", set(">=1.2.3 && <2.0.0-0").String())
+	require.Equal(t, "~1.2.3", set(">=1.2.3 && <1.3.0-0").String())
+	require.Equal(t, "^0.2.3", set("~0.2.3").String())
+	require.True(t, set("~1").equal(set("^1.0.0")))
+	require.Equal(t, "=1.2.3", set(">=1.2.3 && <=1.2.3").String())
+	require.Equal(t, "!(=1.2.3)", set("<1.2.3 || >1.2.3").String())
+	require.Equal(t, "(>1.0.0 && <=2.0.0)", set(">1.0.0 && <=2.0.0").String())
+	require.Equal(t, "(>=1.0.0-rc.0 && <2.0.0)", set(">1.0.0-rc && <2.0.0").String())
+	require.Equal(t, "(<1.0.0 || >=3.0.0)", set(">=3.0.0 || <1.0.0").String())
+	require.Equal(t, "<0.0.0-0", set(">2.0.0 && <1.0.0").String())
+	require.True(t, set(set(">2.0.0 && <1.0.0").String()).isEmpty())
+	require.Equal(t, "", set("").String())
+}
+
+func TestVersionNumbersArithmetic(t *testing.T) {
+	require.Equal(t, "1", incNumber("0"))
+	require.Equal(t, "10", incNumber("9"))
+	require.Equal(t, "1000", incNumber("999"))
+	require.Equal(t, "124", incNumber("123"))
+	require.Equal(t, "0", decNumber("1"))
+	require.Equal(t, "9", decNumber("10"))
+	require.Equal(t, "999", decNumber("1000"))
+	require.Equal(t, "122", decNumber("123"))
+
+	four := func(s string) *Version {
+		res, err := ParseWithOptions(s, ParseOptions{AllowFourComponents: true})
+		require.NoError(t, err)
+		return res
+	}
+	require.Equal(t, "1.2.3.1-0", successor(v("1.2.3")).String())
+	require.Equal(t, "1.0.0.1-0", successor(v("1+build")).String())
+	require.Equal(t, "1.2.3-rc.0", successor(v("1.2.3-rc")).String())
+	require.Equal(t, "1.2.3.5-0", successor(four("1.2.3.4")).String())
+	require.Equal(t, "1.2.3.4-rc.0", successor(four("1.2.3.4-rc")).String())
+	require.Equal(t, "1.2.3", predecessor(four("1.2.3.1-0")).String())
+	require.Equal(t, "1.2.3.4", predecessor(four("1.2.3.5-0")).String())
+	require.Nil(t, predecessor(v("1.2.4-0")))
+	require.Nil(t, predecessor(four("1.2.4.1-1")))
+	require.Equal(t, "1.2.3.4-rc", versionBound(four("1.2.3.4-rc+build")).String())
+	require.Equal(t, "2.0.0-0", caretUpperBound(v("1.2.3")).String())
+	require.Equal(t, "0.3.0-0", caretUpperBound(v("0.2.3")).String())
+	require.Equal(t, "0.0.4-0", caretUpperBound(v("0.0.3")).String())
+	require.Equal(t, "1.3.0-0", tildeUpperBound(v("1.2.3")).String())
+	require.Equal(t, "1.3.0-0", tildeUpperBound(v("1.2")).String())
+	require.Equal(t, "2.0.0-0", tildeUpperBound(v("1")).String())
+	require.Equal(t, "0.1.0-0", tildeUpperBound(v("0.0.3-rc")).String())
+}
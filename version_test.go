@@ -9,6 +9,7 @@ package semver
 import (
 	"cmp"
 	"fmt"
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -361,3 +362,157 @@ func TestVersionGetters(t *testing.T) {
 		require.Equal(t, tt.build, r.BuildMetadata())
 	}
 }
+
+func TestVersionBump(t *testing.T) {
+	inc := func(f func(*Version) *Version, in, expected string) {
+		res := f(MustParse(in))
+		require.Equal(t, expected, res.String(), "bumping %s", in)
+	}
+	inc((*Version).IncMajor, "1.2.3", "2.0.0")
+	inc((*Version).IncMajor, "1.2.3-rc+build", "2.0.0")
+	inc((*Version).IncMajor, "2.0.0-rc", "2.0.0")
+	inc((*Version).IncMajor, "1", "2")
+	inc((*Version).IncMajor, "1.2", "2.0")
+	inc((*Version).IncMajor, "1.2-beta", "2.0")
+	inc((*Version).IncMajor, "2-beta", "2")
+	inc((*Version).IncMajor, "", "1")
+	inc((*Version).IncMajor, "9.0.0", "10.0.0")
+
+	inc((*Version).IncMinor, "1.2.3", "1.3.0")
+	inc((*Version).IncMinor, "1.3.0-rc", "1.3.0")
+	inc((*Version).IncMinor, "1.2.3-rc", "1.3.0")
+	inc((*Version).IncMinor, "1", "1.1")
+	inc((*Version).IncMinor, "1.2", "1.3")
+	inc((*Version).IncMinor, "1.2-beta", "1.2")
+	inc((*Version).IncMinor, "1.9.0+build", "1.10.0")
+
+	inc((*Version).IncPatch, "1.2.3", "1.2.4")
+	inc((*Version).IncPatch, "1.2.4-rc.1", "1.2.4")
+	inc((*Version).IncPatch, "1", "1.0.1")
+	inc((*Version).IncPatch, "1.2", "1.2.1")
+	inc((*Version).IncPatch, "1.2-beta", "1.2")
+	inc((*Version).IncPatch, "1.2.3+build", "1.2.4")
+
+	incPre := func(in, identifier, expected string) {
+		res, err := MustParse(in).IncPrerelease(identifier)
+		require.NoError(t, err)
+		require.Equal(t, expected, res.String(), "bumping %s with %s", in, identifier)
+	}
+	incPre("1.2.3", "rc", "1.2.4-rc.0")
+	incPre("1.2.3", "", "1.2.4-0")
+	incPre("1.2", "beta", "1.2.1-beta.0")
+	incPre("1.2.3-rc.1", "rc", "1.2.3-rc.2")
+	incPre("1.2.3-rc.9+build", "rc", "1.2.3-rc.10")
+	incPre("1.2.3-rc", "rc", "1.2.3-rc.0")
+	incPre("1.2.3-alpha.1", "beta", "1.2.3-beta.0")
+	incPre("1.2.3-alpha.1", "alpha.1", "1.2.3-alpha.1.0")
+	incPre("1.2.3-alpha.1.x", "", "1.2.3-alpha.2.x")
+	incPre("1.2.3-alpha", "", "1.2.3-alpha.0")
+	incPre("1.2-beta.1", "", "1.2-beta.2")
+	incPre("1.2-beta.1", "beta", "1.2-beta.2")
+	_, err := MustParse("1.2.3").IncPrerelease("rc!")
+	require.Error(t, err)
+	_, err = MustParse("1.2.3-rc").IncPrerelease("a..b")
+	require.Error(t, err)
+
+	with := func(res *Version, err error) string {
+		require.NoError(t, err)
+		return res.String()
+	}
+	require.Equal(t, "1.2.3-rc.1+build", with(MustParse("1.2.3+build").WithPrerelease("rc.1")))
+	require.Equal(t, "1.2-rc", with(MustParse("1.2-beta").WithPrerelease("rc")))
+	require.Equal(t, "1.2.3+build", with(MustParse("1.2.3-beta+build").WithPrerelease("")))
+	require.Equal(t, "1.2.3-beta+b.2", with(MustParse("1.2.3-beta+build").WithBuild("b.2")))
+	require.Equal(t, "1-beta", with(MustParse("1-beta+build").WithBuild("")))
+	_, err = MustParse("1.2.3").WithPrerelease("01")
+	require.Error(t, err)
+	_, err = MustParse("1.2.3").WithBuild("a_b")
+	require.Error(t, err)
+
+	// The original version is not modified
+	v := MustParse("1.2.3-rc.1+build")
+	v.IncMajor()
+	v.IncMinor()
+	v.IncPatch()
+	_, _ = v.IncPrerelease("rc")
+	_, _ = v.WithBuild("b")
+	_, _ = v.WithPrerelease("b")
+	require.Equal(t, "1.2.3-rc.1+build", v.String())
+
+	// The prefix and the fourth number of the versions parsed with options
+	opts := ParseOptions{AllowPrefix: true, AllowLeadingZeros: true, AllowFourComponents: true}
+	withOptions := func(in string) *Version {
+		v, err := ParseWithOptions(in, opts)
+		require.NoError(t, err)
+		return v
+	}
+	require.Equal(t, "v1.2.4", withOptions("v1.2.3").IncPatch().String())
+	require.Equal(t, "V2.0.0", withOptions("V1.02.3").IncMajor().String())
+	require.Equal(t, "v1.3", withOptions("v1.2").IncMinor().String())
+	require.Equal(t, "1.2.4", withOptions("1.2.3.4").IncPatch().String())
+	require.Equal(t, "1.2.3.4", withOptions("1.2.3.4-rc").IncPatch().String())
+	require.Equal(t, "1.4.0", withOptions("1.3.0.1-rc").IncMinor().String())
+	require.Equal(t, "2.0.0", withOptions("1.0.0.1-rc").IncMajor().String())
+	require.Equal(t, "v1.2.3.4-rc.2", with(withOptions("v1.2.3.4-rc.1").IncPrerelease("rc")))
+	require.Equal(t, "1.2.4-rc.0", with(withOptions("1.2.3.4").IncPrerelease("rc")))
+	require.Equal(t, "v1.2.3.4-beta", with(withOptions("v1.2.3.4-rc").WithPrerelease("beta")))
+	v = withOptions("1.2.3.4")
+	b, err := v.WithBuild("x")
+	require.NoError(t, err)
+	require.Equal(t, "1.2.3.4+x", b.String())
+	require.True(t, b.Equal(v))
+	b, err = withOptions("v1.2.3.4+x").WithBuild("")
+	require.NoError(t, err)
+	require.Equal(t, "v1.2.3.4", b.String())
+}
+
+func TestVersionNumericAccessors(t *testing.T) {
+	numbers := func(in string) []uint64 {
+		v := MustParse(in)
+		return []uint64{v.Major(), v.Minor(), v.Patch()}
+	}
+	require.Equal(t, []uint64{1, 2, 3}, numbers("1.2.3-rc.1+build"))
+	require.Equal(t, []uint64{1, 2, 0}, numbers("1.2-rc"))
+	require.Equal(t, []uint64{1, 0, 0}, numbers("1"))
+	require.Equal(t, []uint64{0, 0, 0}, numbers(""))
+	require.Equal(t, []uint64{18446744073709551615, 0, 10}, numbers("18446744073709551615.0.10"))
+	require.Equal(t, []uint64{math.MaxUint64, 1, 0}, numbers("18446744073709551616.1"))
+
+	huge := MustParse("123456789012345678901234567890.98765432109876543210.1")
+	require.Equal(t, "123456789012345678901234567890", huge.MajorBig().String())
+	require.Equal(t, "98765432109876543210", huge.MinorBig().String())
+	require.Equal(t, "1", huge.PatchBig().String())
+	require.Equal(t, "0", MustParse("1").MinorBig().String())
+
+	v := MustParse("1.2.3-rc.10.x-1.99999999999999999999+build.001.sha")
+	ids := v.PrereleaseIdentifiers()
+	require.Len(t, ids, 4)
+	require.Equal(t, "rc", ids[0].String())
+	require.False(t, ids[0].IsNumeric())
+	n, ok := ids[0].Uint64()
+	require.False(t, ok)
+	require.Zero(t, n)
+	require.Nil(t, ids[0].BigInt())
+	require.True(t, ids[1].IsNumeric())
+	n, ok = ids[1].Uint64()
+	require.True(t, ok)
+	require.Equal(t, uint64(10), n)
+	require.Equal(t, "x-1", ids[2].String())
+	require.False(t, ids[2].IsNumeric())
+	require.True(t, ids[3].IsNumeric())
+	_, ok = ids[3].Uint64()
+	require.False(t, ok)
+	require.Equal(t, "99999999999999999999", ids[3].BigInt().String())
+	require.Equal(t, []string{"build", "001", "sha"}, v.BuildIdentifiers())
+
+	require.Nil(t, MustParse("1.2.3").PrereleaseIdentifiers())
+	require.Nil(t, MustParse("1.2.3").BuildIdentifiers())
+
+	// Identifiers follow the semver precedence rules
+	require.Equal(t, -1, ids[1].CompareTo(ids[3]))
+	require.Equal(t, 1, ids[3].CompareTo(ids[1]))
+	require.Equal(t, -1, ids[1].CompareTo(ids[0]))
+	require.Equal(t, 1, ids[0].CompareTo(ids[1]))
+	require.Equal(t, 1, ids[2].CompareTo(ids[0]))
+	require.Equal(t, 0, ids[2].CompareTo(ids[2]))
+}
@@ -12,7 +12,7 @@ import (
 
 // MarshalYAML implements yaml.Marshaler
 func (v *Version) MarshalYAML() (interface{}, error) {
-	return v.String(), nil
+	return v.encodedString()
 }
 
 // UnmarshalYAML implements yaml.Unmarshaler
@@ -33,6 +33,8 @@ func (v *Version) UnmarshalYAML(node *yaml.Node) error {
 	v.patch = parsed.patch
 	v.prerelease = parsed.prerelease
 	v.build = parsed.build
+	v.spelling = ""
+	v.revision = ""
 	return nil
 }
 
@@ -51,5 +53,7 @@ func (v *RelaxedVersion) UnmarshalYAML(node *yaml.Node) error {
 
 	v.customversion = parsed.customversion
 	v.version = parsed.version
+	v.coerced = parsed.coerced
+	v.ordering = parsed.ordering
 	return nil
 }
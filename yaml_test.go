@@ -95,3 +95,26 @@ func BenchmarkYAMLDecodingRelaxed(b *testing.B) {
 		_ = yaml.Unmarshal(data, &u)
 	}
 }
+
+func TestYAMLParseVersionWithOptions(t *testing.T) {
+	v, err := ParseWithOptions("V01.02.3-rc+build", ParseOptions{AllowPrefix: true, AllowLeadingZeros: true, AllowFourComponents: true})
+	require.NoError(t, err)
+	data, err := yaml.Marshal(v)
+	require.NoError(t, err)
+	require.Equal(t, "1.2.3-rc+build\n", string(data))
+	var u Version
+	require.NoError(t, yaml.Unmarshal(data, &u))
+	require.Equal(t, "1.2.3-rc+build", u.String())
+	require.Zero(t, v.CompareTo(&u))
+
+	// The fourth number can't be encoded
+	v, err = ParseWithOptions("1.2.3.4", ParseOptions{AllowPrefix: true, AllowLeadingZeros: true, AllowFourComponents: true})
+	require.NoError(t, err)
+	_, err = yaml.Marshal(v)
+	require.Error(t, err)
+
+	// The decoder is strict
+	for _, in := range []string{`"v1.2.3"`, `"01.02.03"`, `"1.2.3.4"`, `"V0001.2"`} {
+		require.Error(t, yaml.Unmarshal([]byte(in), &u), in)
+	}
+}